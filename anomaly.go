@@ -0,0 +1,133 @@
+package mwnd
+
+import "math"
+
+// AnomalyDetector scores each value against the exponentially weighted mean and
+// standard deviation of the values before it, and flags values whose z-score exceeds
+// a threshold as anomalous.
+//
+// All operations on the AnomalyDetector take constant time.
+type AnomalyDetector[T Numeric] struct {
+	baseline *ExponentialWindow[T]
+
+	// threshold is the absolute z-score at which a value becomes anomalous, and
+	// release is the absolute z-score below which values stop being anomalous.
+	threshold, release float64
+	warmup             int
+	exclude            bool
+
+	anomalous bool
+	score     float64
+}
+
+// AnomalyOption configures optional behavior of an AnomalyDetector.
+type AnomalyOption func(*anomalyOptions)
+
+type anomalyOptions struct {
+	warmup  int
+	release float64
+	exclude bool
+}
+
+// WithWarmup configures an AnomalyDetector to never flag the first n values,
+// which only serve to establish the baseline.
+func WithWarmup(n int) AnomalyOption {
+	return func(o *anomalyOptions) {
+		o.warmup = n
+	}
+}
+
+// WithHysteresis configures an AnomalyDetector to remain in the anomalous state
+// until the absolute z-score of a value falls below release, which should be
+// less than the threshold. This avoids flapping when values hover around the
+// threshold.
+func WithHysteresis(release float64) AnomalyOption {
+	return func(o *anomalyOptions) {
+		o.release = release
+	}
+}
+
+// WithBaselineExclusion configures an AnomalyDetector to not update its baseline
+// with anomalous values, so that sustained spikes don't drag the mean towards them.
+// Note that a persistent shift in level will then remain anomalous until the
+// values return to the baseline.
+func WithBaselineExclusion() AnomalyOption {
+	return func(o *anomalyOptions) {
+		o.exclude = true
+	}
+}
+
+// NewAnomalyDetector initializes an AnomalyDetector whose baseline is an
+// [ExponentialWindow] with weight alpha, and which flags values with an absolute
+// z-score greater than or equal to threshold.
+func NewAnomalyDetector[T Numeric](alpha, threshold float64, opts ...AnomalyOption) *AnomalyDetector[T] {
+	o := anomalyOptions{release: threshold}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &AnomalyDetector[T]{
+		baseline:  Exponential[T](alpha),
+		threshold: threshold,
+		release:   min(o.release, threshold),
+		warmup:    o.warmup,
+		exclude:   o.exclude,
+	}
+}
+
+// Baseline returns the ExponentialWindow against which values are scored.
+// Callers must not Put values into it directly.
+func (d *AnomalyDetector[T]) Baseline() *ExponentialWindow[T] {
+	return d.baseline
+}
+
+// Score returns the z-score of the most recent value passed to Put.
+func (d *AnomalyDetector[T]) Score() float64 {
+	return d.score
+}
+
+// Anomalous reports whether the most recent value passed to Put was anomalous.
+func (d *AnomalyDetector[T]) Anomalous() bool {
+	return d.anomalous
+}
+
+// Put scores v against the current baseline and then adds it to the baseline.
+// It returns the z-score of v and whether v is anomalous. During the warm-up
+// period, the z-score is always zero.
+//
+// If the baseline has no variance, then any value that differs from the mean
+// has an infinite z-score.
+//
+// Time complexity of O(1).
+func (d *AnomalyDetector[T]) Put(v T) (score float64, anomalous bool) {
+	if d.baseline.Size() < max(d.warmup, 1) {
+		d.score = 0
+		d.anomalous = false
+		d.baseline.Put(v)
+		return d.score, d.anomalous
+	}
+
+	delta := float64(v) - d.baseline.Mean()
+	stddev := math.Sqrt(d.baseline.Variance())
+	switch {
+	case delta == 0:
+		d.score = 0
+	case stddev == 0:
+		d.score = math.Copysign(math.Inf(1), delta)
+	default:
+		d.score = delta / stddev
+	}
+
+	abs := math.Abs(d.score)
+	if d.anomalous {
+		d.anomalous = abs >= d.release
+	} else {
+		d.anomalous = abs >= d.threshold
+	}
+
+	if !d.anomalous || !d.exclude {
+		d.baseline.Put(v)
+	}
+
+	return d.score, d.anomalous
+}
@@ -0,0 +1,85 @@
+package mwnd
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_anomalyDetector_Put(t *testing.T) {
+	t.Run("warm up", func(t *testing.T) {
+		d := NewAnomalyDetector[int](0.1, 3, WithWarmup(3))
+		for _, v := range []int{1, 1000, -1000} {
+			score, anomalous := d.Put(v)
+			assertEqual(t, 0.0, score, "should not score during warm up")
+			assertEqual(t, false, anomalous, "should not flag during warm up")
+		}
+		assertEqual(t, 3, d.Baseline().Size())
+
+		_, anomalous := d.Put(100000)
+		assertEqual(t, true, anomalous, "should flag after warm up")
+	})
+
+	t.Run("no variance", func(t *testing.T) {
+		d := NewAnomalyDetector[int](0.1, 3)
+		d.Put(5)
+		score, anomalous := d.Put(5)
+		assertEqual(t, 0.0, score)
+		assertEqual(t, false, anomalous)
+
+		score, anomalous = d.Put(4)
+		assertEqual(t, math.Inf(-1), score)
+		assertEqual(t, true, anomalous)
+	})
+
+	t.Run("spike", func(t *testing.T) {
+		d := NewAnomalyDetector[float64](0.1, 3, WithWarmup(10))
+		for i := range 100 {
+			_, anomalous := d.Put(float64(i % 2))
+			assertEqual(t, false, anomalous)
+		}
+
+		score, anomalous := d.Put(10)
+		assertLessOrEqual(t, 3.0, score)
+		assertEqual(t, true, anomalous)
+		assertEqual(t, score, d.Score())
+		assertEqual(t, true, d.Anomalous())
+
+		_, anomalous = d.Put(0)
+		assertEqual(t, false, anomalous, "should recover immediately without hysteresis")
+	})
+
+	t.Run("hysteresis", func(t *testing.T) {
+		d := NewAnomalyDetector[float64](0.1, 3, WithWarmup(10), WithHysteresis(1), WithBaselineExclusion())
+		for i := range 100 {
+			d.Put(float64(i % 2))
+		}
+
+		stddev := math.Sqrt(d.Baseline().Variance())
+		mean := d.Baseline().Mean()
+
+		_, anomalous := d.Put(mean + 4*stddev)
+		assertEqual(t, true, anomalous, "should flag above threshold")
+
+		_, anomalous = d.Put(mean + 2*stddev)
+		assertEqual(t, true, anomalous, "should stay flagged above release")
+
+		_, anomalous = d.Put(mean + 0.5*stddev)
+		assertEqual(t, false, anomalous, "should clear below release")
+	})
+
+	t.Run("baseline exclusion", func(t *testing.T) {
+		d := NewAnomalyDetector[float64](0.1, 3, WithWarmup(10), WithBaselineExclusion())
+		for i := range 100 {
+			d.Put(float64(i % 2))
+		}
+
+		size := d.Baseline().Size()
+		mean := d.Baseline().Mean()
+		for range 10 {
+			_, anomalous := d.Put(100)
+			assertEqual(t, true, anomalous, "sustained spike should remain anomalous")
+		}
+		assertEqual(t, size, d.Baseline().Size(), "should not add anomalous values to the baseline")
+		assertEqual(t, mean, d.Baseline().Mean(), "should not move the mean")
+	})
+}
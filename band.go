@@ -0,0 +1,115 @@
+package mwnd
+
+import "math"
+
+// Band computes Bollinger-style bands around the mean of a Window, at a multiple
+// of the Window's standard deviation.
+//
+// A Band reads from its Window on every call, so it always reflects the values
+// currently in the Window. All operations take constant time.
+type Band[T Numeric] struct {
+	w Window[T]
+
+	// k is the multiple of the standard deviation used by PercentB and Bandwidth
+	k float64
+}
+
+// NewBand initializes a Band over w, where k is the multiple of the standard
+// deviation used by [Band.PercentB] and [Band.Bandwidth]. Bollinger bands
+// conventionally use k = 2.
+func NewBand[T Numeric](w Window[T], k float64) *Band[T] {
+	return &Band[T]{w: w, k: k}
+}
+
+// Middle returns the mean of the Window.
+func (b *Band[T]) Middle() float64 {
+	return b.w.Mean()
+}
+
+// StdDev returns the population standard deviation of the Window.
+func (b *Band[T]) StdDev() float64 {
+	return math.Sqrt(b.w.Variance())
+}
+
+// Upper returns the mean of the Window plus k standard deviations.
+func (b *Band[T]) Upper(k float64) float64 {
+	return b.w.Mean() + k*b.StdDev()
+}
+
+// Lower returns the mean of the Window minus k standard deviations.
+func (b *Band[T]) Lower(k float64) float64 {
+	return b.w.Mean() - k*b.StdDev()
+}
+
+// PercentB returns the position of v relative to the bands at the k passed to
+// NewBand, where 0 is the lower band and 1 is the upper band. Values outside the
+// band are below 0 or above 1. If the band has zero width, then it returns NaN.
+func (b *Band[T]) PercentB(v T) float64 {
+	return percentB(float64(v), b.Lower(b.k), b.Upper(b.k))
+}
+
+// Bandwidth returns the width between the bands at the k passed to NewBand,
+// relative to the mean. If the mean is zero, then it returns NaN or ±Inf.
+func (b *Band[T]) Bandwidth() float64 {
+	return (b.Upper(b.k) - b.Lower(b.k)) / b.Middle()
+}
+
+// QuantileBand computes a band between two quantiles of a FixedWindow, centered
+// on the median. Unlike Band, it makes no assumption about the distribution of the
+// values, so it's well suited for skewed data such as latencies.
+//
+// Worst case time complexity of each operation is O(log n), where n is the number
+// of values in the Window.
+type QuantileBand[T Numeric] struct {
+	w      *FixedWindow[T]
+	lo, hi float64
+}
+
+// NewQuantileBand initializes a QuantileBand over w with a lower band at the
+// quantile lo and an upper band at the quantile hi. For example, lo = 0.05 and
+// hi = 0.95 bound the middle 90% of values.
+//
+// NewQuantileBand panics if lo or hi is not between 0.0 and 1.0, inclusive,
+// or if lo is greater than hi.
+func NewQuantileBand[T Numeric](w *FixedWindow[T], lo, hi float64) *QuantileBand[T] {
+	if !(validQuantile(lo) && validQuantile(hi) && lo <= hi) {
+		panic("lo and hi must satisfy 0.0 <= lo <= hi <= 1.0")
+	}
+
+	return &QuantileBand[T]{w: w, lo: lo, hi: hi}
+}
+
+// Middle returns the median of the Window.
+func (b *QuantileBand[T]) Middle() T {
+	return b.w.Quantile(0.5)
+}
+
+// Upper returns the upper quantile of the Window.
+func (b *QuantileBand[T]) Upper() T {
+	return b.w.Quantile(b.hi)
+}
+
+// Lower returns the lower quantile of the Window.
+func (b *QuantileBand[T]) Lower() T {
+	return b.w.Quantile(b.lo)
+}
+
+// PercentB returns the position of v relative to the band, where 0 is the lower
+// band and 1 is the upper band. Values outside the band are below 0 or above 1.
+// If the band has zero width, then it returns NaN.
+func (b *QuantileBand[T]) PercentB(v T) float64 {
+	return percentB(float64(v), float64(b.Lower()), float64(b.Upper()))
+}
+
+// Bandwidth returns the width of the band relative to the median. If the median
+// is zero, then it returns NaN or ±Inf.
+func (b *QuantileBand[T]) Bandwidth() float64 {
+	return (float64(b.Upper()) - float64(b.Lower())) / float64(b.Middle())
+}
+
+func percentB(v, lower, upper float64) float64 {
+	if upper == lower {
+		return math.NaN()
+	}
+	return (v - lower) / (upper - lower)
+}
@@ -0,0 +1,88 @@
+package mwnd
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_band(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		b := NewBand[int](Fixed[int](3), 2)
+		assertEqual(t, 0.0, b.Middle())
+		assertEqual(t, 0.0, b.Upper(2))
+		assertEqual(t, 0.0, b.Lower(2))
+		assertInDelta(t, math.NaN(), b.PercentB(1), 0)
+		assertInDelta(t, math.NaN(), b.Bandwidth(), 0)
+	})
+
+	t.Run("fixed", func(t *testing.T) {
+		// Mean of 5 and standard deviation of 2
+		b := NewBand[int](makeFixed(2, 4, 4, 4, 5, 5, 7, 9), 2)
+		assertEqual(t, 5.0, b.Middle())
+		assertEqual(t, 2.0, b.StdDev())
+		assertEqual(t, 9.0, b.Upper(2))
+		assertEqual(t, 1.0, b.Lower(2))
+		assertEqual(t, 8.0, b.Upper(1.5))
+		assertEqual(t, 2.0, b.Lower(1.5))
+		assertEqual(t, 0.0, b.PercentB(1))
+		assertEqual(t, 0.5, b.PercentB(5))
+		assertEqual(t, 1.0, b.PercentB(9))
+		assertEqual(t, 1.25, b.PercentB(11))
+		assertEqual(t, 1.6, b.Bandwidth())
+
+		narrow := NewBand[int](makeFixed(2, 4, 4, 4, 5, 5, 7, 9), 1.5)
+		assertEqual(t, 1.0, narrow.PercentB(8), "should use the k of NewBand")
+		assertEqual(t, 0.0, narrow.PercentB(2), "should use the k of NewBand")
+		assertEqual(t, 1.2, narrow.Bandwidth())
+	})
+
+	t.Run("exponential", func(t *testing.T) {
+		w := Exponential[float64](0.5)
+		b := NewBand[float64](w, 2)
+		w.Put(1)
+		w.Put(3)
+		assertEqual(t, w.Mean(), b.Middle())
+		assertInDelta(t, w.Mean()+2*math.Sqrt(w.Variance()), b.Upper(2), 1e-12)
+		assertInDelta(t, w.Mean()-2*math.Sqrt(w.Variance()), b.Lower(2), 1e-12)
+	})
+}
+
+func Test_quantileBand(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		b := NewQuantileBand(Fixed[int](3), 0.1, 0.9)
+		assertEqual(t, 0, b.Middle())
+		assertEqual(t, 0, b.Upper())
+		assertEqual(t, 0, b.Lower())
+		assertInDelta(t, math.NaN(), b.PercentB(1), 0)
+	})
+
+	t.Run("ten nodes", func(t *testing.T) {
+		b := NewQuantileBand(makeFixed(3, 6, 7, 8, 8, 10, 13, 15, 16, 20), 0.25, 0.75)
+		assertEqual(t, 8, b.Middle())
+		assertEqual(t, 7, b.Lower())
+		assertEqual(t, 15, b.Upper())
+		assertEqual(t, 0.0, b.PercentB(7))
+		assertEqual(t, 0.5, b.PercentB(11))
+		assertEqual(t, 1.0, b.PercentB(15))
+		assertEqual(t, 1.0, b.Bandwidth())
+	})
+
+	t.Run("invalid quantiles", func(t *testing.T) {
+		cases := map[string][2]float64{
+			"reversed": {0.9, 0.1},
+			"negative": {-0.1, 0.9},
+			"above 1":  {0.1, 1.1},
+			"NaN lo":   {math.NaN(), 0.9},
+			"NaN hi":   {0.1, math.NaN()},
+		}
+
+		for name, c := range cases {
+			func() {
+				defer func() {
+					assertEqual(t, "lo and hi must satisfy 0.0 <= lo <= hi <= 1.0", recover(), name)
+				}()
+				NewQuantileBand(Fixed[int](3), c[0], c[1])
+			}()
+		}
+	})
+}
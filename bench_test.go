@@ -2,6 +2,10 @@ package mwnd_test
 
 import (
 	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/davidbacisin/go-mwnd"
@@ -33,6 +37,32 @@ func BenchmarkExponential(b *testing.B) {
 	}
 }
 
+func BenchmarkMinMax_1000(b *testing.B) {
+	w, _ := mwnd.NewMinMax[int](1000)
+	for b.Loop() {
+		v := rand.Int()
+		w.Put(v)
+
+		if w.Min() < 0 || w.Max() < 0 || w.Mean() < 0.0 {
+			b.Logf("invalid min, max, or mean")
+			b.FailNow()
+		}
+	}
+}
+
+func BenchmarkFixed_1000_MinMaxOnly(b *testing.B) {
+	w := mwnd.Fixed[int](1000, mwnd.WithStats(mwnd.Mean|mwnd.MinMax))
+	for b.Loop() {
+		v := rand.Int()
+		w.Put(v)
+
+		if w.Min() < 0 || w.Max() < 0 || w.Mean() < 0.0 {
+			b.Logf("invalid min, max, or mean")
+			b.FailNow()
+		}
+	}
+}
+
 func BenchmarkFixed_1000_Quantiles(b *testing.B) {
 	cases := []struct {
 		name string
@@ -62,3 +92,261 @@ func BenchmarkFixed_1000_Quantiles(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkFixed_1000_SixQuantiles(b *testing.B) {
+	qs := []float64{0.1, 0.25, 0.5, 0.75, 0.9, 0.99}
+
+	b.Run("Quantile", func(b *testing.B) {
+		w := mwnd.Fixed[float64](1000)
+		for b.Loop() {
+			w.Put(rand.Float64())
+			for _, q := range qs {
+				if w.Quantile(q) < 0.0 {
+					b.FailNow()
+				}
+			}
+		}
+	})
+
+	b.Run("Quantiles", func(b *testing.B) {
+		w := mwnd.Fixed[float64](1000)
+		dst := make([]float64, 0, len(qs))
+		b.ReportAllocs()
+		for b.Loop() {
+			w.Put(rand.Float64())
+			dst, _ = w.Quantiles(dst[:0], qs...)
+			if dst[0] < 0.0 {
+				b.FailNow()
+			}
+		}
+	})
+
+	b.Run("TrackQuantile", func(b *testing.B) {
+		w := mwnd.Fixed[float64](1000)
+		cursors := make([]*mwnd.QuantileCursor[float64], len(qs))
+		for i, q := range qs {
+			cursors[i] = w.TrackQuantile(q)
+		}
+
+		for b.Loop() {
+			w.Put(rand.Float64())
+			for _, c := range cursors {
+				if c.Value() < 0.0 {
+					b.FailNow()
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkMedian_1000(b *testing.B) {
+	b.Run("Fixed", func(b *testing.B) {
+		w := mwnd.Fixed[float64](1000)
+		for b.Loop() {
+			w.Put(rand.Float64())
+			if w.Quantile(0.5) < 0.0 {
+				b.FailNow()
+			}
+		}
+	})
+
+	b.Run("Median", func(b *testing.B) {
+		w, _ := mwnd.NewMedian[float64](1000)
+		for b.Loop() {
+			w.Put(rand.Float64())
+			if w.Median() < 0.0 {
+				b.FailNow()
+			}
+		}
+	})
+}
+
+// pointerNode mirrors the layout of FixedWindow nodes before they referred to each
+// other by index, for comparing memory usage and garbage collection cost.
+type pointerNode struct {
+	value               int
+	parent, left, right *pointerNode
+	color               bool
+	nLeft, nRight       int
+}
+
+// makePointerTree links n pointerNodes into a complete binary tree, similar to the
+// shape of a full FixedWindow.
+func makePointerTree(n int) []pointerNode {
+	nodes := make([]pointerNode, n)
+	for i := 1; i < n; i++ {
+		p := &nodes[(i-1)/2]
+		nodes[i].parent = p
+		if i%2 == 1 {
+			p.left = &nodes[i]
+		} else {
+			p.right = &nodes[i]
+		}
+	}
+	return nodes
+}
+
+func BenchmarkFixed_MemoryPerElement(b *testing.B) {
+	const n = 1_000_000
+
+	reportBytesPerElement := func(b *testing.B, alloc func()) {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		for b.Loop() {
+			alloc()
+		}
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.TotalAlloc-before.TotalAlloc)/float64(b.N)/n, "B/elem")
+	}
+
+	b.Run("index", func(b *testing.B) {
+		reportBytesPerElement(b, func() {
+			runtime.KeepAlive(mwnd.Fixed[int](n))
+		})
+	})
+
+	b.Run("pointer", func(b *testing.B) {
+		reportBytesPerElement(b, func() {
+			runtime.KeepAlive(make([]pointerNode, n))
+		})
+	})
+}
+
+// BenchmarkFixed_GC measures the time of a full garbage collection while a
+// window of a million values is live.
+func BenchmarkFixed_GC(b *testing.B) {
+	const n = 1_000_000
+
+	b.Run("index", func(b *testing.B) {
+		w := mwnd.Fixed[int](n)
+		for range n {
+			w.Put(rand.Int())
+		}
+
+		for b.Loop() {
+			runtime.GC()
+		}
+		runtime.KeepAlive(w)
+	})
+
+	b.Run("pointer", func(b *testing.B) {
+		nodes := makePointerTree(n)
+		for b.Loop() {
+			runtime.GC()
+		}
+		runtime.KeepAlive(nodes)
+	})
+}
+
+func BenchmarkFixed_WarmStart(b *testing.B) {
+	const capacity = 100_000
+	values := make([]float64, capacity)
+	for i := range values {
+		values[i] = rand.Float64()
+	}
+
+	b.Run("Put", func(b *testing.B) {
+		for b.Loop() {
+			w := mwnd.Fixed[float64](capacity)
+			for _, v := range values {
+				w.Put(v)
+			}
+		}
+	})
+
+	b.Run("PutAll", func(b *testing.B) {
+		for b.Loop() {
+			w := mwnd.Fixed[float64](capacity)
+			w.PutAll(values)
+		}
+	})
+
+	sorted := slices.Sorted(slices.Values(values))
+	b.Run("PutAll_Sorted", func(b *testing.B) {
+		for b.Loop() {
+			w := mwnd.Fixed[float64](capacity)
+			w.PutAll(sorted)
+		}
+	})
+}
+
+// BenchmarkPublisher_ParallelReaders measures reads of the statistics of a window by
+// parallel readers while a single writer Puts values as fast as it can. The puts/s
+// metric shows how much the readers slow down the writer.
+func BenchmarkPublisher_ParallelReaders(b *testing.B) {
+	run := func(b *testing.B, put func(int), read func() float64) {
+		var puts atomic.Int64
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				put(rand.Int())
+				puts.Add(1)
+			}
+		}()
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if read() < 0.0 {
+					b.Error("invalid mean")
+				}
+			}
+		})
+
+		close(stop)
+		<-done
+		b.ReportMetric(float64(puts.Load())/b.Elapsed().Seconds(), "puts/s")
+	}
+
+	b.Run("Mutex", func(b *testing.B) {
+		var mu sync.Mutex
+		w := mwnd.Fixed[int](1000)
+		run(b, func(v int) {
+			mu.Lock()
+			w.Put(v)
+			mu.Unlock()
+		}, func() float64 {
+			mu.Lock()
+			defer mu.Unlock()
+			return w.Mean() + float64(w.Quantile(0.5))
+		})
+	})
+
+	b.Run("Publisher", func(b *testing.B) {
+		p := mwnd.NewPublisher[int](mwnd.Fixed[int](1000), 100, 0.5)
+		run(b, p.Put, func() float64 {
+			s := p.Load()
+			v, _ := s.Quantile(0.5)
+			return s.Mean + float64(v)
+		})
+	})
+}
+
+func BenchmarkExponential_ParallelPut(b *testing.B) {
+	b.Run("Mutex", func(b *testing.B) {
+		var mu sync.Mutex
+		w := mwnd.Exponential[float64](0.002)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				mu.Lock()
+				w.Put(rand.Float64())
+				mu.Unlock()
+			}
+		})
+	})
+
+	b.Run("Sharded", func(b *testing.B) {
+		w, _ := mwnd.NewShardedExponential[float64](0.002, runtime.GOMAXPROCS(0))
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				w.Put(rand.Float64())
+			}
+		})
+	})
+}
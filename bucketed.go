@@ -0,0 +1,278 @@
+package mwnd
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// BucketedWindow aggregates the values of a trailing period of time in a ring of
+// buckets, each of which covers an equal slice of that period. Rather than storing
+// values, each bucket stores only the count, mean, sum of squared differences, min,
+// and max of its values, which are merged on read.
+//
+// Memory usage is constant in the number of values, which makes BucketedWindow
+// suitable for very large windows such as a day of high-frequency samples. In
+// exchange, values expire a whole bucket at a time, so the window covers between
+// n-1 and n bucket widths of time, where n is the number of buckets.
+//
+// Quantiles are only available if the window is created with [WithSketch], which
+// adds a mergeable quantile sketch to each bucket.
+type BucketedWindow[T Numeric] struct {
+	buckets []bucket[T]
+	width   time.Duration
+	now     func() time.Time
+
+	// mapping assigns values to the bins of the sketch of each bucket, which are
+	// only kept if sketched is true
+	mapping  ddMapping
+	sketched bool
+
+	// scratch holds merged bin counts during Quantile to avoid allocations
+	scratch []uint64
+}
+
+type bucket[T Numeric] struct {
+	// epoch is the number of bucket widths since the Unix epoch at the start of the bucket
+	epoch    int64
+	min, max T
+	moments
+	sketch ddSketch
+}
+
+// enforce compliance with interface
+var _ Window[float64] = (*BucketedWindow[float64])(nil)
+
+// NewBucketed initializes a moving window that covers the given number of buckets,
+// each of the given width. For example, NewBucketed[float64](time.Minute, 1440)
+// covers the last 24 hours at a granularity of one minute. Pass [WithSketch] to
+// estimate quantiles.
+//
+// It returns an error wrapping [ErrInvalidWidth] if width is not positive,
+// [ErrInvalidCapacity] if the number of buckets is invalid, as for the capacity of
+// [NewFixed], or [ErrInvalidAccuracy] if the relative accuracy passed to WithSketch
+// is not between 0.0 and 1.0, exclusive.
+func NewBucketed[T Numeric](width time.Duration, buckets int, opts ...Option) (*BucketedWindow[T], error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWidth, width)
+	}
+	if err := validCapacity(buckets); err != nil {
+		return nil, err
+	}
+
+	o := newOptions(opts)
+	w := &BucketedWindow[T]{
+		buckets: make([]bucket[T], buckets),
+		width:   width,
+		now:     time.Now,
+	}
+
+	if o.sketch {
+		if err := validAccuracy(o.sketchAccuracy); err != nil {
+			return nil, err
+		}
+		w.mapping = newDDMapping(o.sketchAccuracy)
+		w.sketched = true
+	}
+
+	return w, nil
+}
+
+func (w *BucketedWindow[T]) epoch(t time.Time) int64 {
+	e := t.UnixNano() / int64(w.width)
+	if t.UnixNano() < 0 && t.UnixNano()%int64(w.width) != 0 {
+		e--
+	}
+	return e
+}
+
+func (w *BucketedWindow[T]) slot(epoch int64) *bucket[T] {
+	n := int64(len(w.buckets))
+	return &w.buckets[((epoch%n)+n)%n]
+}
+
+// live reports whether the bucket b has values within the window as of the epoch now.
+func (w *BucketedWindow[T]) live(b *bucket[T], now int64) bool {
+	return b.n > 0 && b.epoch > now-int64(len(w.buckets)) && b.epoch <= now
+}
+
+// merged combines all buckets that are within the window as of now.
+func (w *BucketedWindow[T]) merged() (m moments, lo, hi T) {
+	return w.mergedAt(w.epoch(w.now()))
+}
+
+// mergedAt combines all buckets that are within the window as of the epoch now.
+func (w *BucketedWindow[T]) mergedAt(now int64) (m moments, lo, hi T) {
+	for i := range w.buckets {
+		b := &w.buckets[i]
+		if !w.live(b, now) {
+			continue
+		}
+
+		if m.n == 0 {
+			lo, hi = b.min, b.max
+		} else {
+			lo, hi = min(lo, b.min), max(hi, b.max)
+		}
+		m.merge(b.moments)
+	}
+
+	return m, lo, hi
+}
+
+// Size returns the number of values in the Window.
+//
+// Time complexity of O(b), where b is the number of buckets.
+func (w *BucketedWindow[T]) Size() int {
+	m, _, _ := w.merged()
+	return m.n
+}
+
+// Min returns the lowest value in the Window.
+// If the Window has no values, then it returns the zero value.
+//
+// Time complexity of O(b), where b is the number of buckets.
+func (w *BucketedWindow[T]) Min() T {
+	_, lo, _ := w.merged()
+	return lo
+}
+
+// Max returns the highest value in the Window.
+// If the Window has no values, then it returns the zero value.
+//
+// Time complexity of O(b), where b is the number of buckets.
+func (w *BucketedWindow[T]) Max() T {
+	_, _, hi := w.merged()
+	return hi
+}
+
+// Mean returns the arithmetic mean of all values in the Window.
+// If the Window has no values, then it returns 0.0.
+//
+// Time complexity of O(b), where b is the number of buckets.
+func (w *BucketedWindow[T]) Mean() float64 {
+	m, _, _ := w.merged()
+	return m.mean
+}
+
+// Variance returns the population variance of all values in the Window.
+// If the Window has no values, then it returns 0.0.
+//
+// Time complexity of O(b), where b is the number of buckets.
+func (w *BucketedWindow[T]) Variance() float64 {
+	m, _, _ := w.merged()
+	return m.variance()
+}
+
+// Quantile returns an estimate of the value for which the probability of another value
+// being less than or equal to that value is q, within the relative accuracy passed to
+// [WithSketch]. If the Window has no values or wasn't created with WithSketch, then it
+// returns the zero value.
+//
+// Time complexity of O(b·k), where b is the number of buckets and k is the number of
+// bins in each sketch.
+func (w *BucketedWindow[T]) Quantile(q float64) T {
+	if !validQuantile(q) {
+		panic("q must be between 0.0 and 1.0, inclusive")
+	}
+
+	if !w.sketched {
+		return 0
+	}
+
+	now := w.epoch(w.now())
+	m, lo, hi := w.mergedAt(now)
+	return w.quantileAt(now, m, lo, hi, q)
+}
+
+// QuantileE is like [BucketedWindow.Quantile], but returns an error wrapping
+// [ErrInvalidQuantile] instead of panicking if q is outside of the range 0.0 to 1.0,
+// inclusive.
+func (w *BucketedWindow[T]) QuantileE(q float64) (T, error) {
+	if !validQuantile(q) {
+		var zero T
+		return zero, fmt.Errorf("%w: %v", ErrInvalidQuantile, q)
+	}
+	return w.Quantile(q), nil
+}
+
+// quantileAt estimates the quantile q by merging the sketches of all buckets that are
+// within the window as of the epoch now, given their merged moments, min, and max.
+func (w *BucketedWindow[T]) quantileAt(now int64, m moments, lo, hi T, q float64) T {
+	if m.n == 0 {
+		return 0
+	}
+
+	sketches := func(yield func(*ddSketch) bool) {
+		for i := range w.buckets {
+			if b := &w.buckets[i]; w.live(b, now) && !yield(&b.sketch) {
+				return
+			}
+		}
+	}
+
+	v, ok := w.mapping.quantile(sketches, uint64(quantileRank(m.n, q)), &w.scratch)
+	if !ok {
+		return hi
+	}
+	return clampEstimate(v, lo, hi)
+}
+
+// Summary returns a snapshot of the statistics of the Window, including an estimate
+// of each quantile in qs if the Window was created with [WithSketch]. Quantiles
+// outside of the range 0.0 to 1.0, inclusive, are omitted. Without WithSketch, the
+// Summary reports the quantiles as Missing.
+//
+// Time complexity of O(b·k), where b is the number of buckets and k is the number of
+// quantiles and bins in each sketch.
+func (w *BucketedWindow[T]) Summary(qs ...float64) Summary[T] {
+	now := w.epoch(w.now())
+	m, lo, hi := w.mergedAt(now)
+	s := Summary[T]{
+		Count:    m.n,
+		Min:      lo,
+		Max:      hi,
+		Mean:     m.mean,
+		Variance: m.variance(),
+		StdDev:   math.Sqrt(m.variance()),
+	}
+
+	if len(qs) > 0 && !w.sketched {
+		s.Missing |= Quantiles
+	} else if qs = validQuantilesOf(qs); len(qs) > 0 {
+		s.Quantiles = make([]QuantileValue[T], len(qs))
+		for i, q := range qs {
+			s.Quantiles[i] = QuantileValue[T]{Q: q, Value: w.quantileAt(now, m, lo, hi, q)}
+		}
+	}
+
+	return s
+}
+
+// Put adds a new value to the bucket for the current time, replacing the contents
+// of that bucket if they have expired.
+//
+// Time complexity of O(1).
+func (w *BucketedWindow[T]) Put(v T) {
+	e := w.epoch(w.now())
+	b := w.slot(e)
+	if b.n > 0 && b.epoch > e {
+		// The clock moved backwards by more than the length of the window, so
+		// the value would already have expired.
+		return
+	}
+
+	if b.epoch != e || b.n == 0 {
+		// Reuse the bins of the expired sketch
+		b.epoch, b.min, b.max = e, v, v
+		b.moments = moments{}
+		b.sketch.reset()
+	}
+
+	b.min = min(b.min, v)
+	b.max = max(b.max, v)
+	b.add(float64(v))
+	if w.sketched {
+		b.sketch.add(w.mapping, float64(v))
+	}
+}
@@ -0,0 +1,179 @@
+package mwnd
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_bucketed(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	w, _ := NewBucketed[int](time.Second, 3)
+	w.now = func() time.Time { return now }
+
+	assertEqual(t, 0, w.Size())
+	assertEqual(t, 0, w.Min())
+	assertEqual(t, 0, w.Max())
+	assertEqual(t, 0.0, w.Mean())
+	assertEqual(t, 0.0, w.Variance())
+
+	w.Put(2)
+	w.Put(4)
+	now = now.Add(time.Second)
+	w.Put(6)
+	assertEqual(t, 3, w.Size())
+	assertEqual(t, 2, w.Min())
+	assertEqual(t, 6, w.Max())
+	assertEqual(t, 4.0, w.Mean())
+	assertEqual(t, 8.0/3.0, w.Variance())
+
+	now = now.Add(2 * time.Second)
+	assertEqual(t, 1, w.Size(), "should expire the first bucket on read")
+	assertEqual(t, 6, w.Min())
+	assertEqual(t, 6, w.Max())
+	assertEqual(t, 6.0, w.Mean())
+	assertEqual(t, 0.0, w.Variance())
+
+	w.Put(1)
+	assertEqual(t, 2, w.Size(), "should replace the expired bucket")
+	assertEqual(t, 1, w.Min())
+	assertEqual(t, 6, w.Max())
+
+	now = now.Add(-5 * time.Second)
+	w.Put(100)
+	now = now.Add(5 * time.Second)
+	assertEqual(t, 2, w.Size(), "should drop values that are already expired")
+	assertEqual(t, 6, w.Max())
+
+	now = now.Add(time.Hour)
+	assertEqual(t, 0, w.Size(), "should expire all buckets")
+	assertEqual(t, 0, w.Max())
+}
+
+func Test_bucketed_Summary(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	w, _ := NewBucketed[int](time.Second, 3)
+	w.now = func() time.Time { return now }
+	w.Put(2)
+	now = now.Add(time.Second)
+	w.Put(4)
+
+	s := w.Summary()
+	assertEqual(t, 2, s.Count)
+	assertEqual(t, 2, s.Min)
+	assertEqual(t, 4, s.Max)
+	assertEqual(t, 3.0, s.Mean)
+	assertEqual(t, 1.0, s.Variance)
+	assertEqual(t, Stat(0), s.Missing)
+
+	s = w.Summary(0.5)
+	assertEqual(t, 2, s.Count)
+	assertEqual(t, 0, len(s.Quantiles))
+	assertEqual(t, true, s.Missing == Quantiles, "should report quantiles as missing without a sketch")
+
+	ws, _ := NewBucketed[int](time.Second, 3, WithSketch(0.01))
+	ws.now = w.now
+	for _, v := range []int{3, 6, 7, 8, 8, 10, 13, 15, 16, 20} {
+		ws.Put(v)
+	}
+	s = ws.Summary(0.5, 2, 0.25)
+	assertEqual(t, 10, s.Count)
+	assertEqual(t, Stat(0), s.Missing)
+	assertEqual(t, 2, len(s.Quantiles), "should omit invalid quantiles")
+	for _, q := range []float64{0.5, 0.25} {
+		v, ok := s.Quantile(q)
+		assertEqual(t, true, ok)
+		assertEqual(t, ws.Quantile(q), v)
+	}
+	assertEqual(t, true, reflect.DeepEqual(s, summarize[int](ws, []float64{0.5, 2, 0.25})), "should be used by summarize")
+}
+
+func Test_bucketed_Quantile(t *testing.T) {
+	const accuracy = 0.01
+
+	t.Run("without sketch", func(t *testing.T) {
+		w, _ := NewBucketed[int](time.Second, 3)
+		w.Put(5)
+		assertEqual(t, 0, w.Quantile(0.5))
+	})
+
+	t.Run("with sketch", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		w, _ := NewBucketed[float64](time.Second, 3, WithSketch(accuracy))
+		w.now = func() time.Time { return now }
+		assertEqual(t, 0.0, w.Quantile(0.5))
+
+		// Each second holds values of a different magnitude, so that expiring the
+		// first bucket visibly shifts the quantiles
+		var live []float64
+		for sec := range 4 {
+			if sec == 3 {
+				live = live[100:]
+			}
+			for i := range 100 {
+				v := float64(i+1) * math.Pow(10, float64(sec)) * rand.Float64()
+				w.Put(v)
+				live = append(live, v)
+			}
+			now = now.Add(time.Second)
+		}
+		now = now.Add(-time.Second)
+
+		if !assertEqual(t, len(live), w.Size()) {
+			return
+		}
+
+		slices.Sort(live)
+		for _, q := range []float64{0, 0.1, 0.5, 0.9, 1} {
+			want := live[max(1, int(math.Ceil(float64(len(live))*q)))-1]
+			assertInDelta(t, want, w.Quantile(q), want*accuracy, "quantile should be within relative accuracy")
+		}
+
+		_, err := w.QuantileE(2)
+		assertEqual(t, true, errors.Is(err, ErrInvalidQuantile))
+	})
+
+	t.Run("many orders of magnitude", func(t *testing.T) {
+		w, _ := NewBucketed[float64](time.Hour, 3, WithSketch(accuracy))
+		values := []float64{1, 1e30, -1e-20, 0, 1e-300, -1e200, 42}
+		for _, v := range values {
+			w.Put(v)
+		}
+		assertEqual(t, len(values), w.Size())
+		assertInDelta(t, -1e200, w.Quantile(0), 1e200*accuracy)
+		assertInDelta(t, 1e30, w.Quantile(1), 1e30*accuracy)
+
+		// The lowest positive bins are collapsed, so only the order is exact
+		prev := w.Quantile(0)
+		for q := 0.1; q <= 1; q += 0.1 {
+			v := w.Quantile(q)
+			assertLessOrEqual(t, prev, v, "quantiles should not decrease")
+			prev = v
+		}
+	})
+}
+
+func Test_NewBucketed(t *testing.T) {
+	cases := map[string]struct {
+		width   time.Duration
+		buckets int
+		opts    []Option
+		want    error
+	}{
+		"zero width":       {0, 3, nil, ErrInvalidWidth},
+		"negative width":   {-time.Second, 3, nil, ErrInvalidWidth},
+		"zero buckets":     {time.Second, 0, nil, ErrInvalidCapacity},
+		"zero accuracy":    {time.Second, 3, []Option{WithSketch(0)}, ErrInvalidAccuracy},
+		"invalid accuracy": {time.Second, 3, []Option{WithSketch(1.5)}, ErrInvalidAccuracy},
+	}
+
+	for name, c := range cases {
+		w, err := NewBucketed[int](c.width, c.buckets, c.opts...)
+		assertEqual(t, true, w == nil, name)
+		assertEqual(t, true, errors.Is(err, c.want), name)
+	}
+}
@@ -0,0 +1,132 @@
+package mwnd
+
+import "slices"
+
+// QuantileCursor follows the value of a quantile of a FixedWindow as values are Put,
+// so that reading it doesn't require a search of the tree. Create one with
+// [FixedWindow.TrackQuantile].
+type QuantileCursor[T Numeric] struct {
+	w *FixedWindow[T]
+	q float64
+
+	// n is the node of the quantile, and rank is its 1-indexed rank. Both are zero
+	// if the Window is empty.
+	n    int32
+	rank int32
+}
+
+// TrackQuantile returns a cursor that follows the quantile q of the Window. Each insert
+// or delete moves the cursor by at most one position in the order of values, so the
+// cost of maintaining it is amortized into Put, and [QuantileCursor.Value] is O(1).
+// If the Window doesn't track [Quantiles], then the cursor always has the zero value.
+//
+// Call [QuantileCursor.Close] once the cursor is no longer needed, since every cursor
+// adds to the cost of Put.
+func (t *FixedWindow[T]) TrackQuantile(q float64) *QuantileCursor[T] {
+	if !validQuantile(q) {
+		panic("q must be between 0.0 and 1.0, inclusive")
+	}
+
+	c := &QuantileCursor[T]{w: t, q: q}
+	c.sync()
+	t.cursors = append(t.cursors, c)
+	return c
+}
+
+// Value returns the value of the quantile, which is the same as [FixedWindow.Quantile]
+// would return. If the Window has no values, then it returns the zero value.
+//
+// Time complexity of O(1).
+func (c *QuantileCursor[T]) Value() T {
+	if c.n == nilNode {
+		var zero T
+		return zero
+	}
+	return c.w.nodes[c.n].value
+}
+
+// Close stops the cursor from following the quantile. Its value is unspecified afterward.
+func (c *QuantileCursor[T]) Close() {
+	c.w.cursors = slices.DeleteFunc(c.w.cursors, func(o *QuantileCursor[T]) bool {
+		return o == c
+	})
+}
+
+// sync positions the cursor by searching the tree, such as after the tree is rebuilt.
+func (c *QuantileCursor[T]) sync() {
+	t := c.w
+	if t.n == 0 || t.nodes == nil {
+		c.n, c.rank = nilNode, 0
+		return
+	}
+
+	c.rank = quantileRank(t.n, c.q)
+	c.n = t.selectRank(c.rank)
+}
+
+// settle moves the cursor to the rank of the quantile for the current size of the
+// Window, which is at most one position away after Put.
+func (c *QuantileCursor[T]) settle() {
+	if c.w.n == 0 {
+		c.n, c.rank = nilNode, 0
+		return
+	}
+
+	ns := c.w.nodes
+	target := quantileRank(c.w.n, c.q)
+	for c.rank < target {
+		c.n = ns.next(c.n)
+		c.rank++
+	}
+	for c.rank > target {
+		c.n = ns.prev(c.n)
+		c.rank--
+	}
+}
+
+// cursorsForInsert updates and settles the cursors of the Window after n is inserted
+// into the tree.
+func (t *FixedWindow[T]) cursorsForInsert(n int32) {
+	ns := t.nodes
+	for _, c := range t.cursors {
+		switch {
+		case c.n == nilNode:
+			c.n, c.rank = n, 1
+		case ns[n].value < ns[c.n].value:
+			// Equal values are inserted after each other, so only a lower value
+			// shifts the cursor
+			c.rank++
+		}
+		c.settle()
+	}
+}
+
+// cursorsForDelete updates the cursors of the Window before n is deleted from the tree.
+// Since Put always inserts after deleting, the cursors are only settled after the insert,
+// which avoids moving them back and forth when the size of the Window doesn't change.
+func (t *FixedWindow[T]) cursorsForDelete(n int32) {
+	ns := t.nodes
+	for _, c := range t.cursors {
+		switch {
+		case c.n == n:
+			// Move off of the deleted node, keeping the same rank if possible
+			if next := ns.next(n); next != nilNode {
+				c.n = next
+			} else {
+				c.n = ns.prev(n)
+				c.rank--
+			}
+		case ns[n].value < ns[c.n].value:
+			c.rank--
+		case ns[n].value == ns[c.n].value && ns.rank(n) < c.rank:
+			c.rank--
+		}
+	}
+}
+
+// syncCursors repositions all cursors of the Window by searching the tree.
+func (t *FixedWindow[T]) syncCursors() {
+	for _, c := range t.cursors {
+		c.sync()
+	}
+}
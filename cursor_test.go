@@ -0,0 +1,71 @@
+package mwnd
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_quantileCursor(t *testing.T) {
+	qs := []float64{0.0, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 1.0}
+
+	t.Run("empty", func(t *testing.T) {
+		tr := Fixed[int](3)
+		c := tr.TrackQuantile(0.5)
+		assertEqual(t, 0, c.Value())
+	})
+
+	t.Run("matches quantile", func(t *testing.T) {
+		checkRandomPuts(t, func(ref *FixedWindow[int]) (func(int), func(*testing.T) bool) {
+			cursors := make([]*QuantileCursor[int], len(qs))
+			for i, q := range qs {
+				cursors[i] = ref.TrackQuantile(q)
+			}
+
+			return nil, func(t *testing.T) bool {
+				for i, c := range cursors {
+					if !assertEqual(t, ref.Quantile(qs[i]), c.Value(), fmt.Sprintf("cursor should match quantile %v", qs[i])) {
+						return false
+					}
+				}
+				return true
+			}
+		})
+	})
+
+	t.Run("tracks after creation", func(t *testing.T) {
+		tr := makeFixed(5, 1, 3)
+		c := tr.TrackQuantile(0.5)
+		assertEqual(t, 3, c.Value())
+		tr.Put(0) // replaces 5
+		assertEqual(t, 1, c.Value())
+	})
+
+	t.Run("resyncs after PutAll and Reset", func(t *testing.T) {
+		tr := Fixed[int](4)
+		c := tr.TrackQuantile(0.75)
+		tr.PutAll([]int{8, 2, 6, 4})
+		assertEqual(t, 6, c.Value())
+
+		tr.Reset()
+		assertEqual(t, 0, c.Value())
+		tr.Put(7)
+		assertEqual(t, 7, c.Value())
+	})
+
+	t.Run("close", func(t *testing.T) {
+		tr := Fixed[int](4)
+		a := tr.TrackQuantile(0.5)
+		b := tr.TrackQuantile(0.5)
+		a.Close()
+		assertEqual(t, 1, len(tr.cursors))
+		tr.Put(1)
+		assertEqual(t, 1, b.Value())
+	})
+
+	t.Run("without quantiles", func(t *testing.T) {
+		tr := Fixed[int](4, WithStats(Mean))
+		c := tr.TrackQuantile(0.5)
+		tr.Put(1)
+		assertEqual(t, 0, c.Value())
+	})
+}
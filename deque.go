@@ -0,0 +1,86 @@
+package mwnd
+
+import "slices"
+
+// monotonicDeque tracks the extreme value of a sliding window in amortized constant
+// time. Entries are kept in order of arrival, and any entry that can never again be
+// the extreme is discarded when a newer value arrives. For a minimum, the values of
+// the remaining entries are strictly increasing from front to back; for a maximum,
+// strictly decreasing.
+//
+// The deque is a ring buffer, pre-allocated to the capacity of the window.
+type monotonicDeque[T Numeric] struct {
+	entries []dequeEntry[T]
+
+	// head is the index of the front entry, and n is the number of entries
+	head, n int
+
+	// keepMax selects whether the deque tracks the maximum instead of the minimum
+	keepMax bool
+}
+
+type dequeEntry[T Numeric] struct {
+	// seq is the position in the stream of the value, which identifies when it
+	// leaves the window
+	seq   uint64
+	value T
+}
+
+func newMonotonicDeque[T Numeric](capacity int, keepMax bool) monotonicDeque[T] {
+	return monotonicDeque[T]{
+		entries: make([]dequeEntry[T], capacity),
+		keepMax: keepMax,
+	}
+}
+
+// dominates reports whether a newer value a makes an older value b obsolete.
+func (d *monotonicDeque[T]) dominates(a, b T) bool {
+	if d.keepMax {
+		return a >= b
+	}
+	return a <= b
+}
+
+// push appends v as the value at position seq of the stream, discarding all entries
+// that v dominates.
+func (d *monotonicDeque[T]) push(seq uint64, v T) {
+	for d.n > 0 {
+		back := (d.head + d.n - 1) % len(d.entries)
+		if !d.dominates(v, d.entries[back].value) {
+			break
+		}
+		d.n--
+	}
+
+	d.entries[(d.head+d.n)%len(d.entries)] = dequeEntry[T]{seq: seq, value: v}
+	d.n++
+}
+
+// evict discards the front entry if it is the value at position seq of the stream,
+// which is leaving the window.
+func (d *monotonicDeque[T]) evict(seq uint64) {
+	if d.n > 0 && d.entries[d.head].seq == seq {
+		d.head = (d.head + 1) % len(d.entries)
+		d.n--
+	}
+}
+
+// front returns the extreme value in the window, or the zero value if it is empty.
+func (d *monotonicDeque[T]) front() T {
+	if d.n == 0 {
+		var zero T
+		return zero
+	}
+	return d.entries[d.head].value
+}
+
+func (d *monotonicDeque[T]) reset() {
+	d.head, d.n = 0, 0
+}
+
+// clone returns a copy of the deque that shares no memory with it.
+func (d *monotonicDeque[T]) clone() monotonicDeque[T] {
+	c := *d
+	c.entries = slices.Clone(d.entries)
+	return c
+}
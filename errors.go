@@ -0,0 +1,32 @@
+package mwnd
+
+import "errors"
+
+// ErrInvalidEncoding is returned when decoding malformed binary data.
+var ErrInvalidEncoding = errors.New("mwnd: invalid encoding")
+
+// ErrInvalidQuantile is returned when a quantile is outside of the range 0.0 to 1.0, inclusive.
+var ErrInvalidQuantile = errors.New("mwnd: quantile must be between 0.0 and 1.0, inclusive")
+
+// ErrInvalidCapacity is returned when the capacity of a window is not positive or is too large.
+var ErrInvalidCapacity = errors.New("mwnd: capacity must be between 1 and math.MaxInt32-1, inclusive")
+
+// ErrInvalidAccuracy is returned when the relative accuracy of a quantile sketch is not
+// between 0.0 and 1.0, exclusive.
+var ErrInvalidAccuracy = errors.New("mwnd: relative accuracy must be between 0.0 and 1.0, exclusive")
+
+// ErrInvalidWidth is returned when the width of the buckets of a window is not positive.
+var ErrInvalidWidth = errors.New("mwnd: bucket width must be positive")
+
+// ErrInvalidHop is returned when the hop of a hopping window is not positive.
+var ErrInvalidHop = errors.New("mwnd: hop must be positive")
+
+// ErrNilEmit is returned when the callback that receives the Summaries of a window is nil.
+var ErrNilEmit = errors.New("mwnd: emit must not be nil")
+
+// ErrInvalidAlpha is returned when the alpha of an exponential window is not greater than 0.0
+// and at most 1.0.
+var ErrInvalidAlpha = errors.New("mwnd: alpha must be greater than 0.0 and at most 1.0")
+
+// ErrInvalidShards is returned when the number of shards of a sharded window is not positive.
+var ErrInvalidShards = errors.New("mwnd: number of shards must be positive")
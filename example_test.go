@@ -1,6 +1,7 @@
 package mwnd_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/davidbacisin/go-mwnd"
@@ -53,3 +54,32 @@ func ExampleExponential() {
 	// Mean: 2.63
 	// Variance: 13.74
 }
+
+func ExampleStream() {
+	in := make(chan int)
+	out := make(chan mwnd.Summary[int])
+	go func() {
+		defer close(out)
+		err := mwnd.Stream(context.Background(), in, mwnd.Fixed[int](4), out, mwnd.EmitEvery(3), mwnd.EmitQuantiles(0.5))
+		if err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	go func() {
+		for _, v := range []int{1, 5, 4, 3, 2, 10, 7} {
+			in <- v
+		}
+		close(in)
+	}()
+
+	for s := range out {
+		median, _ := s.Quantile(0.5)
+		fmt.Printf("Count: %d, Mean: %.2f, Median: %d\n", s.Count, s.Mean, median)
+	}
+
+	// Output:
+	// Count: 3, Mean: 3.33, Median: 4
+	// Count: 4, Mean: 4.75, Median: 3
+	// Count: 4, Mean: 5.50, Median: 3
+}
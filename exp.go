@@ -1,5 +1,10 @@
 package mwnd
 
+import (
+	"fmt"
+	"math"
+)
+
 // ExponentialWindow computes exponentially weighted moving window statistics
 // over the input stream.
 //
@@ -18,9 +23,10 @@ type ExponentialWindow[T Numeric] struct {
 }
 
 // enforce compliance with interface
-var _ window[float64] = (*ExponentialWindow[float64])(nil)
+var _ Window[float64] = (*ExponentialWindow[float64])(nil)
 
 // Exponential initializes a moving window with the provided weight alpha.
+// Unlike [NewExponential], it doesn't validate alpha.
 func Exponential[T Numeric](alpha float64) *ExponentialWindow[T] {
 	return &ExponentialWindow[T]{
 		alpha: alpha,
@@ -28,6 +34,25 @@ func Exponential[T Numeric](alpha float64) *ExponentialWindow[T] {
 	}
 }
 
+// NewExponential initializes a moving window with the provided weight alpha. It returns
+// an error wrapping [ErrInvalidAlpha] unless alpha is greater than 0.0 and at most 1.0.
+func NewExponential[T Numeric](alpha float64) (*ExponentialWindow[T], error) {
+	if err := validAlpha(alpha); err != nil {
+		return nil, err
+	}
+	return Exponential[T](alpha), nil
+}
+
+// validAlpha returns an error wrapping [ErrInvalidAlpha] unless alpha is greater than
+// 0.0 and at most 1.0.
+func validAlpha(alpha float64) error {
+	// Written to also reject NaN
+	if !(alpha > 0.0 && alpha <= 1.0) {
+		return fmt.Errorf("%w: %v", ErrInvalidAlpha, alpha)
+	}
+	return nil
+}
+
 // ExponentialAlphaForApproximatingFixed returns an alpha value for an exponential moving window
 // that will approximate the behavior of a fixed moving window of length n.
 func ExponentialAlphaForApproximatingFixed(n int) float64 {
@@ -76,6 +101,30 @@ func (w *ExponentialWindow[T]) Variance() float64 {
 	return w.m2 / float64(w.size)
 }
 
+// Summary returns a snapshot of the statistics of the Window. An ExponentialWindow
+// doesn't support quantiles, so the Summary has no Quantiles, and reports them as
+// Missing if qs isn't empty; qs is accepted so that both window types can be
+// summarized alike. Since an ExponentialWindow weights values unequally, merging its
+// Summary with another treats the Count as the weight of the Summary, which is only
+// an approximation.
+//
+// Time complexity of O(1).
+func (w *ExponentialWindow[T]) Summary(qs ...float64) Summary[T] {
+	s := Summary[T]{
+		Count:    w.size,
+		Min:      w.min,
+		Max:      w.max,
+		Mean:     w.Mean(),
+		Variance: w.Variance(),
+		StdDev:   math.Sqrt(w.Variance()),
+	}
+
+	if len(qs) > 0 {
+		s.Missing = Quantiles
+	}
+	return s
+}
+
 // Put adds a new value to the Window.
 //
 // Time complexity of O(1).
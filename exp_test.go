@@ -0,0 +1,22 @@
+package mwnd
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func Test_NewExponential(t *testing.T) {
+	for _, alpha := range []float64{-0.5, 0.0, 1.5, math.NaN(), math.Inf(1)} {
+		w, err := NewExponential[float64](alpha)
+		assertEqual(t, true, w == nil)
+		assertEqual(t, true, errors.Is(err, ErrInvalidAlpha))
+	}
+
+	for _, alpha := range []float64{0.002, 1.0} {
+		w, err := NewExponential[float64](alpha)
+		assertNil(t, err)
+		w.Put(2)
+		assertEqual(t, 2.0, w.Mean())
+	}
+}
@@ -1,6 +1,12 @@
 package mwnd
 
-import "math"
+import (
+	"fmt"
+	"iter"
+	"math"
+	"math/bits"
+	"slices"
+)
 
 // FixedWindow aggregates a fixed number of values. Once the capacity is reached, each new value causes
 // the oldest value to be evicted from the window.
@@ -9,81 +15,237 @@ import "math"
 // of the window. This is achieved by maintaining a red-black balanced binary tree as the underlying data
 // structure. Furthermore, Put and all statistical operations avoid memory allocations
 // to preserve performance in high-scale environments.
+//
+// If the window is created with [WithStats] and quantiles aren't needed, then the tree is replaced by
+// a plain ring buffer of values, along with monotonic deques if the minimum and maximum are needed.
+// Put then takes amortized O(1) time.
 type FixedWindow[T Numeric] struct {
 	// nodes is a ring buffer of all nodes, pre-allocated to the max capacity of the tree so that
-	// memory allocations are minimized during normal operation.
-	nodes          []node[T]
-	root, min, max *node[T]
+	// memory allocations are minimized during normal operation. The ring starts at index 1, after
+	// the sentinel nilNode. It is nil if the Window doesn't track quantiles.
+	nodes          tree[T]
+	root, min, max int32
+
+	// ring holds the values instead of nodes if the Window doesn't track quantiles,
+	// along with their min and max if the Window tracks them
+	ring  valueRing[T]
+	stats Stat
+
+	// moments holds the size, mean, and total sum of squared differences from the mean
+	moments
+
+	// i represents the position in the ring of nodes of the oldest value, which will
+	// be replaced by the next inserted value
+	i int
+
+	// outlierK is the Tukey fence multiplier for rejecting outliers on Put,
+	// which only applies if rejectOutliers is true
+	outlierK       float64
+	rejectOutliers bool
+	rejected       int
+
+	// streak is the number of consecutive values that Put has rejected as outliers,
+	// after which outliers are accepted again if it reaches a positive readmitAfter
+	streak       int
+	readmitAfter int
+
+	// cursors follow quantiles as values are inserted and deleted
+	cursors []*QuantileCursor[T]
+
+	onEvict  func(old T)
+	onChange func(min, max T)
+
+	// shared reports whether nodes and ring are shared with a Snapshot, in which case
+	// they must be copied before they are modified
+	shared bool
+
+	// last records the most recent Put so that it can be undone
+	last struct {
+		ok, full bool
+		evicted  T
+	}
+}
 
-	// mean is the mean value of all values in the tree
-	mean float64
+// enforce compliance with interface
+var _ Window[float64] = (*FixedWindow[float64])(nil)
 
-	// m2 is the total sum of squared differences from the mean
-	m2 float64
+// Fixed initializes a moving window with the fixed capacity for values.
+// It panics if the capacity is invalid; see [NewFixed].
+func Fixed[T Numeric](capacity int, opts ...Option) *FixedWindow[T] {
+	t, err := NewFixed[T](capacity, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
 
-	// i represents the oldest node in the tree, which will be replaced
-	// by the next inserted value
-	i    int
-	size int
+// NewFixed initializes a moving window with the fixed capacity for values. It returns
+// an error wrapping [ErrInvalidCapacity] if the capacity is less than 1 or not less
+// than [math.MaxInt32].
+func NewFixed[T Numeric](capacity int, opts ...Option) (*FixedWindow[T], error) {
+	if err := validCapacity(capacity); err != nil {
+		return nil, err
+	}
+
+	o := newOptions(opts)
+	t := &FixedWindow[T]{
+		i:              0,
+		stats:          o.stats,
+		outlierK:       o.outlierK,
+		rejectOutliers: o.rejectOutliers,
+		readmitAfter:   o.readmitAfter,
+	}
+
+	if t.rejectOutliers {
+		// Outlier rejection requires the quartiles
+		t.stats |= Quantiles
+	}
+
+	if t.tracks(Quantiles) {
+		t.nodes = make(tree[T], capacity+1)
+	} else {
+		t.ring = newValueRing[T](capacity, t.tracks(MinMax))
+	}
+
+	return t, nil
 }
 
-// enforce compliance with interface
-var _ window[float64] = (*FixedWindow[float64])(nil)
+// validCapacity returns an error wrapping [ErrInvalidCapacity] if capacity is less than
+// 1 or not less than [math.MaxInt32], which is the limit of the int32 indices of
+// FixedWindow and MedianWindow.
+func validCapacity(capacity int) error {
+	if capacity < 1 || capacity >= math.MaxInt32 {
+		return fmt.Errorf("%w: %d", ErrInvalidCapacity, capacity)
+	}
+	return nil
+}
 
-// Fixed initializes a moving window with the fixed capacity for values.
-func Fixed[T Numeric](capacity int) *FixedWindow[T] {
-	return &FixedWindow[T]{
-		nodes: make([]node[T], capacity),
-		i:     0,
-		size:  0,
+// tracks reports whether the Window computes the statistic s.
+func (t *FixedWindow[T]) tracks(s Stat) bool {
+	return t.stats&s != 0
+}
+
+// capacity returns the maximum number of values in the Window.
+func (t *FixedWindow[T]) capacity() int {
+	if t.nodes == nil {
+		return len(t.ring.values)
 	}
+	return len(t.nodes) - 1
 }
 
-func (t *FixedWindow[T]) nodeForPut() *node[T] {
-	next := &t.nodes[t.i]
+func (t *FixedWindow[T]) nodeForPut() int32 {
+	next := int32(t.i + 1)
 
 	// If the node is already in the tree, remove it.
-	if next.parent != nil || next == t.root {
+	if t.nodes[next].parent != nilNode || next == t.root {
 		t.delete(next)
 	}
 
-	t.i = (t.i + 1) % cap(t.nodes)
-	t.size = min(t.size+1, cap(t.nodes))
-
-	// Inserted nodes start as red
-	next.color = red
+	t.i = (t.i + 1) % t.capacity()
 	return next
 }
 
+// Reset removes all values from the Window, retaining its capacity and options.
+//
+// Time complexity of O(n), where n is the capacity of the Window.
+func (t *FixedWindow[T]) Reset() {
+	t.unshare()
+	clear(t.nodes)
+	t.root, t.min, t.max = nilNode, nilNode, nilNode
+	t.ring.reset()
+	t.moments = moments{}
+	t.i = 0
+	t.rejected = 0
+	t.streak = 0
+	t.last.ok = false
+	t.syncCursors()
+}
+
 // Size returns the current number of values in the Window.
 func (t *FixedWindow[T]) Size() int {
-	return t.size
+	return t.n
+}
+
+// At returns the value that was Put age values before the newest value, so that age 0
+// is the newest value. It returns false if age is not less than the size of the Window.
+//
+// Time complexity of O(1).
+func (t *FixedWindow[T]) At(age int) (T, bool) {
+	if age < 0 || age >= t.n {
+		var zero T
+		return zero, false
+	}
+
+	if t.nodes == nil {
+		return t.ring.at(age), true
+	}
+
+	capacity := t.capacity()
+	return t.nodes[(t.i-1-age+2*capacity)%capacity+1].value, true
+}
+
+// Newest returns the value most recently added to the Window. It returns false if the
+// Window has no values.
+//
+// Time complexity of O(1).
+func (t *FixedWindow[T]) Newest() (T, bool) {
+	return t.At(0)
+}
+
+// Oldest returns the value least recently added to the Window, which is the next value
+// to be evicted once the Window is at capacity. It returns false if the Window has no
+// values.
+//
+// Time complexity of O(1).
+func (t *FixedWindow[T]) Oldest() (T, bool) {
+	return t.At(t.n - 1)
+}
+
+// Values appends all values in the Window to dst in the order that they were added,
+// from oldest to newest, and returns the extended slice.
+//
+// Time complexity of O(n), where n is the number of values in the Window.
+func (t *FixedWindow[T]) Values(dst []T) []T {
+	dst = slices.Grow(dst, t.n)
+	for age := t.n - 1; age >= 0; age-- {
+		v, _ := t.At(age)
+		dst = append(dst, v)
+	}
+	return dst
 }
 
 // Min returns the lowest value currently in the Window.
-// If the Window has no values, then it returns the zero value.
+// If the Window has no values or doesn't track [MinMax], then it returns the zero value.
 //
 // Time complexity of O(1).
 func (t *FixedWindow[T]) Min() T {
-	if t == nil || t.min == nil {
+	if t != nil && t.nodes == nil {
+		return t.ring.min()
+	}
+
+	if t == nil || t.min == nilNode {
 		var zero T
 		return zero
 	}
 
-	return t.min.value
+	return t.nodes[t.min].value
 }
 
 // Max returns the highest value currently in the Window.
-// If the Window has no values, then it returns the zero value.
+// If the Window has no values or doesn't track [MinMax], then it returns the zero value.
 //
 // Time complexity of O(1).
 func (t *FixedWindow[T]) Max() T {
-	if t == nil || t.max == nil {
+	if t != nil && t.nodes == nil {
+		return t.ring.max()
+	}
+
+	if t == nil || t.max == nilNode {
 		var zero T
 		return zero
 	}
 
-	return t.max.value
+	return t.nodes[t.max].value
 }
 
 // Mean returns the arithmetic mean of all values currently in the Window.
@@ -99,85 +261,477 @@ func (t *FixedWindow[T]) Mean() float64 {
 //
 // Time complexity of O(1).
 func (t *FixedWindow[T]) Variance() float64 {
-	if t.size == 0 {
-		return 0
-	}
-	return t.m2 / float64(t.size)
+	return t.variance()
 }
 
 // Quantile returns the value for which the probability of another value being
 // less than or equal to that value is q. For example, q = 0.5 returns the median,
 // meaning that half of all values are less than or equal to that median.
+// If the Window has no values or doesn't track [Quantiles], then it returns the zero value.
 //
 // Worst case time complexity of O(log n), where n is the number of values in the Window.
 func (t *FixedWindow[T]) Quantile(q float64) T {
-	if q < 0.0 || q > 1.0 {
+	if !validQuantile(q) {
 		panic("q must be between 0.0 and 1.0, inclusive")
 	}
 
-	if t.size == 0 {
+	if t.n == 0 || t.nodes == nil {
 		return 0
 	}
 
-	n := t.root
+	return t.nodes[t.selectRank(quantileRank(t.n, q))].value
+}
 
-	// i and order are 1-indexed
-	i := int(math.Ceil(float64(t.size) * q))
-	order := 1 + n.nLeft
-	for {
-		if i == order {
-			break
+// QuantileE is like [FixedWindow.Quantile], but returns an error wrapping
+// [ErrInvalidQuantile] instead of panicking if q is outside of the range 0.0 to 1.0,
+// inclusive.
+func (t *FixedWindow[T]) QuantileE(q float64) (T, error) {
+	if !validQuantile(q) {
+		var zero T
+		return zero, fmt.Errorf("%w: %v", ErrInvalidQuantile, q)
+	}
+	return t.Quantile(q), nil
+}
+
+// Quantiles appends the value of each quantile in qs to dst, in the same order as qs,
+// and returns the extended slice. It is equivalent to calling [FixedWindow.Quantile] for
+// each quantile, but resolves all of them in a single traversal of the tree. It doesn't
+// allocate memory if dst has enough spare capacity for len(qs) values.
+//
+// If any quantile is outside of the range 0.0 to 1.0, inclusive, then it returns dst
+// unchanged and an error wrapping [ErrInvalidQuantile].
+//
+// Worst case time complexity of O(k log n), where k is the number of quantiles and n is
+// the number of values in the Window.
+func (t *FixedWindow[T]) Quantiles(dst []T, qs ...float64) ([]T, error) {
+	if err := validQuantiles(qs); err != nil {
+		return dst, err
+	}
+
+	start := len(dst)
+	dst = slices.Grow(dst, len(qs))[:start+len(qs)]
+	out := dst[start:]
+	if t.n == 0 || t.nodes == nil {
+		clear(out)
+		return dst, nil
+	}
+
+	// Sort the quantiles by rank, which avoids allocation for a typical number of
+	// quantiles. Insertion sort is fastest for so few.
+	var buf [16]rankedIndex
+	ranks := buf[:0]
+	if len(qs) > len(buf) {
+		ranks = make([]rankedIndex, 0, len(qs))
+	}
+	for i, q := range qs {
+		r := rankedIndex{rank: quantileRank(t.n, q), i: i}
+		j := len(ranks)
+		ranks = append(ranks, r)
+		for ; j > 0 && ranks[j-1].rank > r.rank; j-- {
+			ranks[j] = ranks[j-1]
+		}
+		ranks[j] = r
+	}
+
+	t.resolveRanks(t.root, 0, ranks, out)
+	return dst, nil
+}
+
+// rankedIndex is the rank of the quantile at index i of the arguments to Quantiles.
+type rankedIndex struct {
+	rank int32
+	i    int
+}
+
+// resolveRanks stores the value at each rank in ranks, which is sorted, into out. All
+// of the ranks must be in the subtree rooted at n, which is preceded in order by base
+// values.
+func (t *FixedWindow[T]) resolveRanks(n int32, base int32, ranks []rankedIndex, out []T) {
+	ns := t.nodes
+	for len(ranks) > 0 {
+		if len(ranks) == 1 {
+			// Once the paths to the ranks diverge, each is a simple walk down the tree
+			out[ranks[0].i] = ns[t.selectRankFrom(n, base, ranks[0].rank)].value
+			return
+		}
+
+		rank := base + ns[n].nLeft + 1
+
+		// Split the ranks into those in the left subtree, at n, and in the right subtree
+		left := 0
+		for left < len(ranks) && ranks[left].rank < rank {
+			left++
+		}
+		right := left
+		for right < len(ranks) && ranks[right].rank == rank {
+			out[ranks[right].i] = ns[n].value
+			right++
+		}
+
+		if left > 0 {
+			t.resolveRanks(ns[n].left, base, ranks[:left], out)
+		}
+
+		// Continue into the right subtree without recursion
+		ranks = ranks[right:]
+		base = rank
+		n = ns[n].right
+	}
+}
+
+// validQuantile reports whether q is between 0.0 and 1.0, inclusive, which excludes NaN.
+func validQuantile(q float64) bool {
+	return q >= 0.0 && q <= 1.0
+}
+
+// validQuantiles returns an error wrapping [ErrInvalidQuantile] if any of qs is
+// outside of the range 0.0 to 1.0, inclusive.
+func validQuantiles(qs []float64) error {
+	for _, q := range qs {
+		if !validQuantile(q) {
+			return fmt.Errorf("%w: %v", ErrInvalidQuantile, q)
 		}
+	}
+	return nil
+}
+
+// validQuantilesOf returns the quantiles in qs that are within the range 0.0 to 1.0,
+// inclusive. It returns qs itself if all of them are.
+func validQuantilesOf(qs []float64) []float64 {
+	if validQuantiles(qs) == nil {
+		return qs
+	}
+	return slices.DeleteFunc(slices.Clone(qs), func(q float64) bool { return !validQuantile(q) })
+}
+
+// quantileRank returns the 1-indexed rank of the quantile q among size values.
+func quantileRank(size int, q float64) int32 {
+	return max(1, int32(math.Ceil(float64(size)*q)))
+}
 
+// selectRank returns the node with the 1-indexed rank i, which must be between 1 and
+// the size of the Window.
+//
+// Worst case time complexity of O(log n), where n is the number of values in the Window.
+func (t *FixedWindow[T]) selectRank(i int32) int32 {
+	return t.selectRankFrom(t.root, 0, i)
+}
+
+// selectRankFrom returns the node with the 1-indexed rank i in the subtree rooted at n,
+// which is preceded in order by base values.
+func (t *FixedWindow[T]) selectRankFrom(n int32, base int32, i int32) int32 {
+	order := base + 1 + t.nodes[n].nLeft
+	for i != order {
 		if i > order {
-			if n.right == nil {
-				break
-			}
-			n = n.right
+			n = t.nodes[n].right
 			// New node is ordered one ahead of its left subtree
-			order += n.nLeft + 1
+			order += t.nodes[n].nLeft + 1
 		} else {
-			if n.left == nil {
-				break
-			}
-			n = n.left
+			n = t.nodes[n].left
 			// New node is ordered behind its parent and right subtree
-			order -= n.nRight + 1
+			order -= t.nodes[n].nRight + 1
 		}
 	}
+	return n
+}
 
-	return n.value
+// Kth returns the k-th lowest value in the Window, where k = 1 is the minimum. It
+// returns false if k is not between 1 and the size of the Window, or if the Window
+// doesn't track [Quantiles].
+//
+// Worst case time complexity of O(log n), where n is the number of values in the Window.
+func (t *FixedWindow[T]) Kth(k int) (T, bool) {
+	if k < 1 || k > t.n || t.nodes == nil {
+		var zero T
+		return zero, false
+	}
+	return t.nodes[t.selectRank(int32(k))].value, true
+}
+
+// KthLargest returns the k-th highest value in the Window, where k = 1 is the maximum.
+// It returns false if k is not between 1 and the size of the Window, or if the Window
+// doesn't track [Quantiles].
+//
+// Worst case time complexity of O(log n), where n is the number of values in the Window.
+func (t *FixedWindow[T]) KthLargest(k int) (T, bool) {
+	return t.Kth(t.n - k + 1)
+}
+
+// Range returns an iterator over the values with ranks i through j, inclusive, where
+// rank 1 is the minimum. If i > j, then the values are in descending order, so the
+// top 10 values are Range(w.Size(), w.Size()-9). Ranks outside of the Window are
+// skipped. The Window must not be modified during iteration.
+//
+// Worst case time complexity of O(log n + k), where k is the number of values in the range
+// and n is the number of values in the Window.
+func (t *FixedWindow[T]) Range(i, j int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if t.n == 0 || t.nodes == nil {
+			return
+		}
+
+		lo, hi := max(min(i, j), 1), min(max(i, j), t.n)
+		if lo > hi {
+			return
+		}
+
+		ns := t.nodes
+		if i <= j {
+			n := t.selectRank(int32(lo))
+			for k := lo; k <= hi && yield(ns[n].value); k++ {
+				n = ns.next(n)
+			}
+			return
+		}
+
+		n := t.selectRank(int32(hi))
+		for k := hi; k >= lo && yield(ns[n].value); k-- {
+			n = ns.prev(n)
+		}
+	}
+}
+
+// Summary returns a snapshot of the statistics of the Window, including the value
+// of each quantile in qs. Quantiles outside of the range 0.0 to 1.0, inclusive, are
+// omitted. If the Window doesn't track [MinMax] or [Quantiles], then the Summary
+// reports them as Missing rather than as zero values.
+//
+// Worst case time complexity of O(log n + k), where k is the number of quantiles and
+// n is the number of values in the Window.
+func (t *FixedWindow[T]) Summary(qs ...float64) Summary[T] {
+	s := Summary[T]{
+		Count:    t.n,
+		Mean:     t.Mean(),
+		Variance: t.Variance(),
+		StdDev:   math.Sqrt(t.Variance()),
+	}
+
+	// The tree tracks the min and max along with quantiles
+	if t.tracks(MinMax | Quantiles) {
+		s.Min, s.Max = t.Min(), t.Max()
+	} else {
+		s.Missing |= MinMax
+	}
+
+	if len(qs) > 0 && !t.tracks(Quantiles) {
+		s.Missing |= Quantiles
+	} else if qs = validQuantilesOf(qs); len(qs) > 0 {
+		values, _ := t.Quantiles(make([]T, 0, len(qs)), qs...)
+		s.Quantiles = make([]QuantileValue[T], len(qs))
+		for i, q := range qs {
+			s.Quantiles[i] = QuantileValue[T]{Q: q, Value: values[i]}
+		}
+	}
+
+	return s
+}
+
+// IQR returns the interquartile range of the values currently in the Window, which is
+// the difference between the third and first quartiles. If the Window has no values,
+// then it returns the zero value.
+//
+// Worst case time complexity of O(log n), where n is the number of values in the Window.
+func (t *FixedWindow[T]) IQR() T {
+	return t.Quantile(0.75) - t.Quantile(0.25)
+}
+
+// IsOutlier classifies v against the Tukey fences of the values currently in the Window.
+// A value is a low outlier if it is less than Q1 - k*IQR and a high outlier if it is
+// greater than Q3 + k*IQR, where Q1 and Q3 are the first and third quartiles. An empty
+// Window, or one that doesn't track [Quantiles], has no outliers.
+//
+// Worst case time complexity of O(log n), where n is the number of values in the Window.
+func (t *FixedWindow[T]) IsOutlier(v T, k float64) (low, high bool) {
+	if t.n == 0 || t.nodes == nil {
+		return false, false
+	}
+
+	q1 := float64(t.Quantile(0.25))
+	q3 := float64(t.Quantile(0.75))
+	fence := k * (q3 - q1)
+	return float64(v) < q1-fence, float64(v) > q3+fence
+}
+
+// Rejected returns the number of values that Put has discarded as outliers.
+// It is always zero unless the Window was created with [WithOutlierRejection].
+func (t *FixedWindow[T]) Rejected() int {
+	return t.rejected
 }
 
 // Put adds a new value to the Window. If the Window is at capacity, then the oldest value is
 // evicted to be replaced by the new value.
 //
+// If the Window was created with [WithOutlierRejection] and is at capacity, then a value
+// that [FixedWindow.IsOutlier] classifies as an outlier is discarded instead. If the
+// Window was also created with [WithOutlierReadmission], then after that many values
+// have been discarded in a row, outliers are accepted until the next value that is not
+// an outlier.
+//
 // Time complexity of O(log n), where n is the number of values in the Window.
 func (t *FixedWindow[T]) Put(v T) {
-	n := t.nodeForPut()
-	n.value = v
+	if t.rejectOutliers && t.n == t.capacity() {
+		if low, high := t.IsOutlier(v, t.outlierK); !low && !high {
+			t.streak = 0
+		} else if t.readmitAfter == 0 || t.streak < t.readmitAfter {
+			t.streak++
+			t.rejected++
+			t.last.ok = false
+			return
+		}
+	}
+
+	t.last.full = t.n == t.capacity()
+	t.last.evicted, _ = t.Oldest()
+	t.last.ok = true
+	t.notifyChange(func() { t.insert(v) })
+
+	if t.last.full && t.onEvict != nil {
+		t.onEvict(t.last.evicted)
+	}
+}
+
+// notifyChange calls f, which modifies the Window, and then calls the OnChange function
+// if f changed the Min or Max.
+func (t *FixedWindow[T]) notifyChange(f func()) {
+	if t.onChange == nil {
+		f()
+		return
+	}
+
+	wasEmpty := t.n == 0
+	oldMin, oldMax := t.Min(), t.Max()
+	f()
+	if newMin, newMax := t.Min(), t.Max(); wasEmpty != (t.n == 0) || newMin != oldMin || newMax != oldMax {
+		t.onChange(newMin, newMax)
+	}
+}
+
+// UndoLast removes the value most recently added by Put and restores the value that it
+// evicted, if any, as if the Put had never happened. Only the most recent Put can be
+// undone, so UndoLast returns false if it is called again, if the Window is empty, or
+// if the most recent Put was rejected as an outlier. It also returns false after Reset
+// or after PutAll or PutSeq rebuild the tree.
+//
+// The OnChange function is called if the Min or Max changes, but the OnEvict function
+// is not called.
+//
+// Worst case time complexity of O(log n), where n is the capacity of the Window, or
+// O(n) if the Window tracks [MinMax] without [Quantiles].
+func (t *FixedWindow[T]) UndoLast() bool {
+	if !t.last.ok || t.n == 0 {
+		return false
+	}
+
+	t.last.ok = false
+	t.notifyChange(func() {
+		t.unshare()
+		if t.nodes == nil {
+			t.remove(float64(t.ring.unpush(t.last.evicted, t.last.full)))
+			if t.last.full {
+				t.add(float64(t.last.evicted))
+			}
+			return
+		}
+
+		capacity := t.capacity()
+		t.i = (t.i - 1 + capacity) % capacity
+		n := int32(t.i + 1)
+		t.delete(n)
+		if t.last.full {
+			t.link(n, t.last.evicted)
+		} else {
+			for _, c := range t.cursors {
+				c.settle()
+			}
+		}
+	})
+	return true
+}
+
+// ReplaceNewest replaces the value most recently added to the Window with v, returning
+// false if the Window is empty. It doesn't affect which value is evicted next, and
+// [FixedWindow.UndoLast] still restores the value evicted by the most recent Put.
+//
+// The OnChange function is called if the Min or Max changes, but the OnEvict function
+// is not called.
+//
+// Worst case time complexity of O(log n), where n is the capacity of the Window, or
+// O(n) if the Window tracks [MinMax] without [Quantiles].
+func (t *FixedWindow[T]) ReplaceNewest(v T) bool {
+	if t.n == 0 {
+		return false
+	}
+
+	t.notifyChange(func() {
+		t.unshare()
+		if t.nodes == nil {
+			t.remove(float64(t.ring.replaceNewest(v)))
+			t.add(float64(v))
+			return
+		}
 
-	// Welford's algorithm for online variance, which is a numerically stable approach.
-	delta := float64(v) - t.mean
-	t.mean += delta / float64(t.size)
-	delta2 := float64(v) - t.mean
-	t.m2 += delta * delta2
+		capacity := t.capacity()
+		n := int32((t.i-1+capacity)%capacity + 1)
+		t.delete(n)
+		t.link(n, v)
+	})
+	return true
+}
+
+// OnEvict sets a function to call with each value that Put evicts because the Window is
+// at capacity, after the new value has been added. Values removed by Reset are not
+// reported. Passing nil removes the function.
+func (t *FixedWindow[T]) OnEvict(f func(old T)) {
+	t.onEvict = f
+}
+
+// OnChange sets a function to call with the new Min and Max whenever Put, UndoLast, or
+// ReplaceNewest changes either of them, including when the Window becomes empty or
+// non-empty. Passing nil removes the function.
+func (t *FixedWindow[T]) OnChange(f func(min, max T)) {
+	t.onChange = f
+}
 
-	if t.root == nil {
+// insert adds v to the Window, evicting the oldest value if the Window is at capacity.
+func (t *FixedWindow[T]) insert(v T) {
+	t.unshare()
+	if t.nodes == nil {
+		if old, evicted := t.ring.push(v); evicted {
+			t.remove(float64(old))
+		}
+		t.add(float64(v))
+		return
+	}
+
+	t.link(t.nodeForPut(), v)
+}
+
+// link inserts the node n, which must not be in the tree, with the value v.
+func (t *FixedWindow[T]) link(n int32, v T) {
+	ns := t.nodes
+	ns[n].value = v
+	t.add(float64(v))
+
+	// Inserted nodes start as red
+	ns[n].color = red
+
+	if t.root == nilNode {
 		t.root = n
 		t.min = n
 		t.max = n
-		n.parent = nil
+		ns[n].parent = nilNode
 		t.rebalanceForInsert(n)
+		t.cursorsForInsert(n)
 		return
 	}
 
 	p := t.root
 	for {
-		if v < p.value {
-			p.nLeft++
-			if p.left == nil {
-				p.setLeft(n)
+		pn := &ns[p]
+		if v < pn.value {
+			pn.nLeft++
+			if pn.left == nilNode {
+				ns.setLeft(p, n)
 
 				if p == t.min {
 					t.min = n
@@ -185,11 +739,11 @@ func (t *FixedWindow[T]) Put(v T) {
 				break
 			}
 
-			p = p.left
+			p = pn.left
 		} else {
-			p.nRight++
-			if p.right == nil {
-				p.setRight(n)
+			pn.nRight++
+			if pn.right == nilNode {
+				ns.setRight(p, n)
 
 				if p == t.max {
 					t.max = n
@@ -197,166 +751,306 @@ func (t *FixedWindow[T]) Put(v T) {
 				break
 			}
 
-			p = p.right
+			p = pn.right
 		}
 	}
 
 	t.rebalanceForInsert(n)
+	t.cursorsForInsert(n)
+}
+
+// PutAll adds each of values to the Window in order, as if by calling Put for each.
+//
+// If values holds at least as many values as the capacity of the Window, then all
+// current values would be evicted anyway, so the tree is instead rebuilt from the last
+// capacity values. The rebuild takes O(n log n) time, or O(n) if those values are
+// already in ascending order, where n is the capacity of the Window. Otherwise, time
+// complexity is O(k log n), where k is the number of values.
+//
+// The tree is never rebuilt if the Window rejects outliers or has an OnEvict or OnChange
+// function, since those depend on each value being Put in turn.
+func (t *FixedWindow[T]) PutAll(values []T) {
+	capacity := t.capacity()
+	if !t.loadable() || len(values) < capacity {
+		for _, v := range values {
+			t.Put(v)
+		}
+		return
+	}
+
+	t.load(values[len(values)-capacity:])
+}
+
+// PutSeq adds each value of seq to the Window in order, as if by calling Put for each.
+// Like [FixedWindow.PutAll], it rebuilds the tree if seq yields at least as many values
+// as the capacity of the Window. To do so, it buffers up to capacity of the most recent
+// values, growing the buffer as values arrive, so the values only appear in the Window
+// once seq is exhausted.
+func (t *FixedWindow[T]) PutSeq(seq iter.Seq[T]) {
+	capacity := t.capacity()
+	if !t.loadable() {
+		for v := range seq {
+			t.Put(v)
+		}
+		return
+	}
+
+	var buf []T
+	k := 0
+	for v := range seq {
+		if len(buf) < capacity {
+			buf = append(buf, v)
+		} else {
+			buf[k%capacity] = v
+		}
+		k++
+	}
+
+	if k < capacity {
+		for _, v := range buf {
+			t.Put(v)
+		}
+		return
+	}
+
+	// Rotate the buffer so that the oldest value is first
+	start := k % capacity
+	slices.Reverse(buf[:start])
+	slices.Reverse(buf[start:])
+	slices.Reverse(buf)
+	t.load(buf)
+}
+
+// loadable reports whether the Window can be rebuilt from a batch of values rather than
+// by calling Put for each.
+func (t *FixedWindow[T]) loadable() bool {
+	return t.nodes != nil && !t.rejectOutliers && t.onEvict == nil && t.onChange == nil
+}
+
+// load replaces all values in the Window with values, which must have exactly the
+// capacity of the Window, by building a balanced tree bottom-up.
+func (t *FixedWindow[T]) load(values []T) {
+	t.unshare()
+	clear(t.nodes)
+	t.moments = moments{}
+
+	// The oldest value is at the start of the ring, so it is the next to be replaced
+	t.i = 0
+	for k, v := range values {
+		t.nodes[k+1].value = v
+		t.add(float64(v))
+	}
+
+	// Sort copies of the values along with their nodes, which is much faster than
+	// sorting nodes by looking up their values.
+	type entry struct {
+		value T
+		n     int32
+	}
+	entries := make([]entry, len(values))
+	for k, v := range values {
+		entries[k] = entry{value: v, n: int32(k + 1)}
+	}
+	if !slices.IsSorted(values) {
+		slices.SortFunc(entries, func(a, b entry) int {
+			switch {
+			case a.value < b.value:
+				return -1
+			case a.value > b.value:
+				return 1
+			}
+			return 0
+		})
+	}
+
+	order := make([]int32, len(entries))
+	for k, e := range entries {
+		order[k] = e.n
+	}
+
+	// Every level of the tree is full except possibly the deepest, so coloring the
+	// deepest level red gives every path the same number of black nodes.
+	t.root = t.build(order, nilNode, 0, bits.Len(uint(len(order)))-1)
+	t.min = order[0]
+	t.max = order[len(order)-1]
+	t.last.ok = false
+	t.syncCursors()
 }
 
-func (t *FixedWindow[T]) rebalanceForInsert(n *node[T]) {
-	p := n.parent
+// build links the nodes in order into a balanced subtree below parent, returning the
+// root of the subtree. Nodes at redDepth are colored red, except for the root of the
+// whole tree.
+func (t *FixedWindow[T]) build(order []int32, parent int32, depth, redDepth int) int32 {
+	if len(order) == 0 {
+		return nilNode
+	}
+
+	mid := len(order) / 2
+	n := order[mid]
+	ns := t.nodes
+	ns[n].parent = parent
+	ns[n].left = t.build(order[:mid], n, depth+1, redDepth)
+	ns[n].right = t.build(order[mid+1:], n, depth+1, redDepth)
+	ns[n].nLeft = int32(mid)
+	ns[n].nRight = int32(len(order) - mid - 1)
+	ns[n].color = black
+	if depth == redDepth && depth > 0 {
+		ns[n].color = red
+	}
+
+	return n
+}
+
+func (t *FixedWindow[T]) rebalanceForInsert(n int32) {
+	ns := t.nodes
+	p := ns[n].parent
 	// Case 1
-	if p == nil {
-		n.color = black
+	if p == nilNode {
+		ns[n].color = black
 		return
 	}
 
 	// Case 2
-	if p.color == black {
+	if ns[p].color == black {
 		return
 	}
 
 	// Case 3
-	g, u := n.grandparentAndUncle()
-	if u.safeColor() == red {
-		p.color = black
-		u.color = black
-		g.color = red
+	g, u := ns.grandparentAndUncle(n)
+	if ns.safeColor(u) == red {
+		ns[p].color = black
+		ns[u].color = black
+		ns[g].color = red
 		t.rebalanceForInsert(g)
 		return
 	}
 
 	// Case 4
-	if n == p.right && p == g.left {
+	if n == ns[p].right && p == ns[g].left {
 		t.rotateLeft(p)
-		n = n.left
-	} else if n == p.left && p == g.right {
+		n = ns[n].left
+	} else if n == ns[p].left && p == ns[g].right {
 		t.rotateRight(p)
-		n = n.right
+		n = ns[n].right
 	}
 
 	// Case 5. Reset the parent and grandparent in case that case 4 rotated
-	p = n.parent
-	g = p.parent
-	p.color = black
-	g.color = red
-	if n == p.left && p == g.left {
+	p = ns[n].parent
+	g = ns[p].parent
+	ns[p].color = black
+	ns[g].color = red
+	if n == ns[p].left && p == ns[g].left {
 		t.rotateRight(g)
-	} else if n == p.right && p == g.right {
+	} else if n == ns[p].right && p == ns[g].right {
 		t.rotateLeft(g)
 	}
 }
 
-func (t *FixedWindow[T]) replace(old, n *node[T]) {
-	if old.parent == nil {
+func (t *FixedWindow[T]) replace(old, n int32) {
+	ns := t.nodes
+	p := ns[old].parent
+	if p == nilNode {
 		t.root = n
-		if n != nil {
-			n.parent = nil
+		if n != nilNode {
+			ns[n].parent = nilNode
 		}
-	} else if old == old.parent.left {
-		old.parent.setLeft(n)
+	} else if old == ns[p].left {
+		ns.setLeft(p, n)
 	} else {
-		old.parent.setRight(n)
+		ns.setRight(p, n)
 	}
 }
 
-func (t *FixedWindow[T]) swap(a, b *node[T]) {
-	if a == b || a == nil || b == nil {
+func (t *FixedWindow[T]) swap(a, b int32) {
+	if a == b || a == nilNode || b == nilNode {
 		return
 	}
 
-	if b.parent == a || b.parent == nil {
+	ns := t.nodes
+	if ns[b].parent == a || ns[b].parent == nilNode {
 		// Swap to reduce number of conditions
 		a, b = b, a
 	}
 
-	a.color, b.color = b.color, a.color
-	aParent, aLeft, aRight := a.parent, a.left, a.right
-	bParent, bLeft, bRight := b.parent, b.left, b.right
+	ns[a].color, ns[b].color = ns[b].color, ns[a].color
+	aParent, aLeft, aRight := ns[a].parent, ns[a].left, ns[a].right
+	bParent, bLeft, bRight := ns[b].parent, ns[b].left, ns[b].right
 
 	var aWasLeft, bWasLeft bool
-	if aParent != nil && aParent.left == a {
+	if aParent != nilNode && ns[aParent].left == a {
 		aWasLeft = true
 	}
 
-	if bParent != nil && bParent.left == b {
+	if bParent != nilNode && ns[bParent].left == b {
 		bWasLeft = true
 	}
 
-	a.setLeft(bLeft)
-	a.setRight(bRight)
-	b.setLeft(aLeft)
-	b.setRight(aRight)
+	ns.setLeft(a, bLeft)
+	ns.setRight(a, bRight)
+	ns.setLeft(b, aLeft)
+	ns.setRight(b, aRight)
 
-	a.nLeft, b.nLeft = b.nLeft, a.nLeft
-	a.nRight, b.nRight = b.nRight, a.nRight
+	ns[a].nLeft, ns[b].nLeft = ns[b].nLeft, ns[a].nLeft
+	ns[a].nRight, ns[b].nRight = ns[b].nRight, ns[a].nRight
 
 	if aParent == b {
 		t.replace(b, a)
 		if aWasLeft {
-			a.setLeft(b)
-			a.nLeft = b.subtreeSize()
+			ns.setLeft(a, b)
+			ns[a].nLeft = ns.subtreeSize(b)
 		} else {
-			a.setRight(b)
-			a.nRight = b.subtreeSize()
+			ns.setRight(a, b)
+			ns[a].nRight = ns.subtreeSize(b)
 		}
 		return
 	}
 
 	t.replace(a, b)
 	if bWasLeft {
-		bParent.setLeft(a)
-		bParent.nLeft = a.subtreeSize()
+		ns.setLeft(bParent, a)
+		ns[bParent].nLeft = ns.subtreeSize(a)
 	} else {
-		bParent.setRight(a)
-		bParent.nRight = a.subtreeSize()
+		ns.setRight(bParent, a)
+		ns[bParent].nRight = ns.subtreeSize(a)
 	}
 }
 
-func (t *FixedWindow[T]) rotateLeft(n *node[T]) {
-	r := n.right
+func (t *FixedWindow[T]) rotateLeft(n int32) {
+	ns := t.nodes
+	r := ns[n].right
 	t.replace(n, r)
 	// Update the deepest node first so that subtree counts are right
-	n.setRight(r.left)
-	n.nRight = r.left.subtreeSize()
-	r.setLeft(n)
-	r.nLeft = n.subtreeSize()
+	ns.setRight(n, ns[r].left)
+	ns[n].nRight = ns.subtreeSize(ns[r].left)
+	ns.setLeft(r, n)
+	ns[r].nLeft = ns.subtreeSize(n)
 }
 
-func (t *FixedWindow[T]) rotateRight(n *node[T]) {
-	l := n.left
+func (t *FixedWindow[T]) rotateRight(n int32) {
+	ns := t.nodes
+	l := ns[n].left
 	t.replace(n, l)
 	// Update the deepest node first so that subtree counts are right
-	n.setLeft(l.right)
-	n.nLeft = l.right.subtreeSize()
-	l.setRight(n)
-	l.nRight = n.subtreeSize()
+	ns.setLeft(n, ns[l].right)
+	ns[n].nLeft = ns.subtreeSize(ns[l].right)
+	ns.setRight(l, n)
+	ns[l].nRight = ns.subtreeSize(n)
 }
 
-func (t *FixedWindow[T]) delete(n *node[T]) {
-	if n == nil {
+func (t *FixedWindow[T]) delete(n int32) {
+	if n == nilNode {
 		return
 	}
 
-	t.size--
+	ns := t.nodes
+	t.remove(float64(ns[n].value))
+	t.cursorsForDelete(n)
 
-	// Adjust the mean and m2 for the removed value
-	if t.size == 0 {
-		t.mean = 0
-		t.m2 = 0
-	} else {
-		delta2 := float64(n.value) - t.mean
-		t.mean -= delta2 / float64(t.size)
-		delta := float64(n.value) - t.mean
-		t.m2 -= delta * delta2
-	}
-
-	if n.left != nil && n.right != nil {
+	if ns[n].left != nilNode && ns[n].right != nilNode {
 		// Find the immediate predecessor
-		pred := n.left
-		for pred.right != nil {
-			pred = pred.right
+		pred := ns[n].left
+		for ns[pred].right != nilNode {
+			pred = ns[pred].right
 		}
 
 		// Swap places with the in-order predecessor
@@ -366,132 +1060,139 @@ func (t *FixedWindow[T]) delete(n *node[T]) {
 		// children, it couldn't possibly be either min or max.
 	}
 
-	wasLeft := n.parent != nil && n.parent.left == n
+	wasLeft := ns[n].parent != nilNode && ns[ns[n].parent].left == n
 
 	// Invariant: n.left, n.right, or both are nil
-	child := n.left
-	if child == nil {
-		child = n.right
+	child := ns[n].left
+	if child == nilNode {
+		child = ns[n].right
 	}
 
 	if n == t.min {
-		if child == nil {
-			t.min = n.parent
+		if child == nilNode {
+			t.min = ns[n].parent
 		} else {
 			t.min = child
 		}
 	}
 
 	if n == t.max {
-		if child == nil {
-			t.max = n.parent
+		if child == nilNode {
+			t.max = ns[n].parent
 		} else {
 			t.max = child
 		}
 	}
 
-	if n.color == black {
-		n.color = child.safeColor()
-		t.rebalanceForDelete(n)
+	if ns[n].color == black {
+		if ns.safeColor(child) == red {
+			// The red child takes the place of its black parent, so
+			// recoloring it restores the black height.
+			ns[child].color = black
+		} else {
+			t.rebalanceForDelete(n)
+		}
 	}
 
-	p := n.parent
+	p := ns[n].parent
 	t.replace(n, child)
 
 	// Bubble up changes in subtree size
-	for p != nil {
+	for p != nilNode {
+		pn := &ns[p]
 		if wasLeft {
-			p.nLeft = child.subtreeSize()
+			pn.nLeft = ns.subtreeSize(child)
 		} else {
-			p.nRight = child.subtreeSize()
+			pn.nRight = ns.subtreeSize(child)
 		}
 
-		child, p = p, p.parent
-		wasLeft = p != nil && p.left == child
+		child, p = p, pn.parent
+		wasLeft = p != nilNode && ns[p].left == child
 	}
 
 	// Remove the node completely from the tree
-	n.parent = nil
-	n.left = nil
-	n.nLeft = 0
-	n.right = nil
-	n.nRight = 0
+	ns[n].parent = nilNode
+	ns[n].left = nilNode
+	ns[n].nLeft = 0
+	ns[n].right = nilNode
+	ns[n].nRight = 0
 }
 
-func (t *FixedWindow[T]) rebalanceForDelete(n *node[T]) {
-	p := n.parent
+func (t *FixedWindow[T]) rebalanceForDelete(n int32) {
+	ns := t.nodes
+	p := ns[n].parent
 	// Case 1
-	if p == nil {
+	if p == nilNode {
 		return
 	}
 
 	// Case 2
-	s := n.sibling()
-	if p.color == black &&
-		s != nil &&
-		s.safeColor() == black &&
-		s.left.safeColor() == black &&
-		s.right.safeColor() == black {
-		s.color = red
+	s := ns.sibling(n)
+	if ns[p].color == black &&
+		s != nilNode &&
+		ns.safeColor(s) == black &&
+		ns.safeColor(ns[s].left) == black &&
+		ns.safeColor(ns[s].right) == black {
+		ns[s].color = red
 		t.rebalanceForDelete(p)
 		return
 	}
 
 	// Case 3
-	if s.safeColor() == red {
-		p.color = red
-		s.color = black
-		if n == p.left {
+	if ns.safeColor(s) == red {
+		ns[p].color = red
+		ns[s].color = black
+		if n == ns[p].left {
 			t.rotateLeft(p)
 		} else {
 			t.rotateRight(p)
 		}
 
 		// Reassign p and s after rotation
-		p = n.parent
-		s = n.sibling()
+		p = ns[n].parent
+		s = ns.sibling(n)
 	}
 
 	// Case 4
-	if p.safeColor() == red &&
-		s != nil &&
-		s.safeColor() == black &&
-		s.left.safeColor() == black &&
-		s.right.safeColor() == black {
-		s.color = red
-		p.color = black
+	if ns.safeColor(p) == red &&
+		s != nilNode &&
+		ns.safeColor(s) == black &&
+		ns.safeColor(ns[s].left) == black &&
+		ns.safeColor(ns[s].right) == black {
+		ns[s].color = red
+		ns[p].color = black
 		return
 	}
 
 	// Case 5
-	if n == p.left &&
-		s != nil &&
-		s.safeColor() == black &&
-		s.left.safeColor() == red &&
-		s.right.safeColor() == black {
-		s.color = red
-		s.left.color = black
+	if n == ns[p].left &&
+		s != nilNode &&
+		ns.safeColor(s) == black &&
+		ns.safeColor(ns[s].left) == red &&
+		ns.safeColor(ns[s].right) == black {
+		ns[s].color = red
+		ns[ns[s].left].color = black
 		t.rotateRight(s)
-	} else if n == p.right &&
-		s != nil &&
-		s.safeColor() == black &&
-		s.right.safeColor() == red &&
-		s.left.safeColor() == black {
-		s.color = red
-		s.right.color = black
+	} else if n == ns[p].right &&
+		s != nilNode &&
+		ns.safeColor(s) == black &&
+		ns.safeColor(ns[s].right) == red &&
+		ns.safeColor(ns[s].left) == black {
+		ns[s].color = red
+		ns[ns[s].right].color = black
 		t.rotateLeft(s)
 	}
 
 	// Case 6
-	p = n.parent
-	s = n.sibling()
-	s.color = p.color
-	p.color = black
-	if n == p.left && s.right.safeColor() == red {
-		s.right.color = black
+	p = ns[n].parent
+	s = ns.sibling(n)
+	ns[s].color = ns[p].color
+	ns[p].color = black
+	if n == ns[p].left && ns.safeColor(ns[s].right) == red {
+		ns[ns[s].right].color = black
 		t.rotateLeft(p)
-	} else if s.left.safeColor() == red {
-		s.left.color = black
+	} else if ns.safeColor(ns[s].left) == red {
+		ns[ns[s].left].color = black
 		t.rotateRight(p)
 	}
 }
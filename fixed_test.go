@@ -1,7 +1,11 @@
 package mwnd
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"math/rand/v2"
+	"runtime"
 	"slices"
 	"testing"
 )
@@ -14,25 +18,27 @@ func makeFixed(values ...int) *FixedWindow[int] {
 	return tr
 }
 
-func assertRedBlackPropertiesNode[T Numeric](t *testing.T, n *node[T]) (total int, blackCount int, ok bool) {
-	if n == nil {
+func assertRedBlackPropertiesNode[T Numeric](t *testing.T, ns tree[T], n int32) (total int32, blackCount int, ok bool) {
+	if n == nilNode {
 		return 0, 0, true
 	}
 
 	total, blackCount, ok = 1, 0, true
-	if n.safeColor() == black {
+	if ns.safeColor(n) == black {
 		blackCount++
 	}
 
 	var (
-		leftTotal, leftBlack, rightTotal, rightBlack int
-		leftOk, rightOk                              bool
+		leftTotal, rightTotal int32
+		leftBlack, rightBlack int
+		leftOk, rightOk       bool
 	)
 
-	if n.left != nil {
-		ok = ok && assertLessOrEqual(t, n.left.value, n.value, "left child should have a lesser or equal value to parent")
+	if l := ns[n].left; l != nilNode {
+		ok = ok && assertEqual(t, n, ns[l].parent, "left child should link to its parent")
+		ok = ok && assertLessOrEqual(t, ns[l].value, ns[n].value, "left child should have a lesser or equal value to parent")
 
-		leftTotal, leftBlack, leftOk = assertRedBlackPropertiesNode(t, n.left)
+		leftTotal, leftBlack, leftOk = assertRedBlackPropertiesNode(t, ns, l)
 		total += leftTotal
 
 		if !leftOk {
@@ -40,10 +46,11 @@ func assertRedBlackPropertiesNode[T Numeric](t *testing.T, n *node[T]) (total in
 		}
 	}
 
-	if n.right != nil {
-		ok = ok && assertLessOrEqual(t, n.value, n.right.value, "right child should have a greater or equal value to parent")
+	if r := ns[n].right; r != nilNode {
+		ok = ok && assertEqual(t, n, ns[r].parent, "right child should link to its parent")
+		ok = ok && assertLessOrEqual(t, ns[n].value, ns[r].value, "right child should have a greater or equal value to parent")
 
-		rightTotal, rightBlack, rightOk = assertRedBlackPropertiesNode(t, n.right)
+		rightTotal, rightBlack, rightOk = assertRedBlackPropertiesNode(t, ns, r)
 		total += rightTotal
 
 		if !rightOk {
@@ -52,18 +59,18 @@ func assertRedBlackPropertiesNode[T Numeric](t *testing.T, n *node[T]) (total in
 	}
 
 	// Subtree sizes
-	if !assertEqual(t, leftTotal, n.nLeft, "incorrect node left subtree count") {
+	if !assertEqual(t, leftTotal, ns[n].nLeft, "incorrect node left subtree count") {
 		return total, blackCount, false
 	}
 
-	if !assertEqual(t, rightTotal, n.nRight, "incorrect node right subtree count") {
+	if !assertEqual(t, rightTotal, ns[n].nRight, "incorrect node right subtree count") {
 		return total, blackCount, false
 	}
 
 	// Red-black properties
-	if n.safeColor() == red {
-		ok = ok && assertEqual(t, black, n.left.safeColor(), "red node should have black left child")
-		ok = ok && assertEqual(t, black, n.right.safeColor(), "red node should have black right child")
+	if ns.safeColor(n) == red {
+		ok = ok && assertEqual(t, black, ns.safeColor(ns[n].left), "red node should have black left child")
+		ok = ok && assertEqual(t, black, ns.safeColor(ns[n].right), "red node should have black right child")
 	}
 
 	assertEqual(t, leftBlack, rightBlack, "should have equal number of black nodes to each leaf")
@@ -71,8 +78,30 @@ func assertRedBlackPropertiesNode[T Numeric](t *testing.T, n *node[T]) (total in
 }
 
 func assertRedBlackProperties[T Numeric](t *testing.T, tr *FixedWindow[T]) bool {
-	_, _, ok := assertRedBlackPropertiesNode(t, tr.root)
-	return ok
+	ok := assertEqual(t, node[T]{}, tr.nodes[nilNode], "sentinel node should never be modified")
+	_, _, rbOk := assertRedBlackPropertiesNode(t, tr.nodes, tr.root)
+	return ok && rbOk
+}
+
+// at returns the index of the node reached by descending from the root of tr
+// along path, where each 'L' or 'R' moves to the left or right child.
+func at[T Numeric](tr *FixedWindow[T], path string) int32 {
+	n := tr.root
+	for _, c := range path {
+		if c == 'L' {
+			n = tr.nodes[n].left
+		} else {
+			n = tr.nodes[n].right
+		}
+	}
+	return n
+}
+
+// newNode sets the value of the node at index v of tr and returns that index, for
+// constructing trees by hand with distinct values no greater than the capacity.
+func newNode(tr *FixedWindow[int], v int) int32 {
+	tr.nodes[v].value = v
+	return int32(v)
 }
 
 func Test_fixed_Insert(t *testing.T) {
@@ -82,69 +111,69 @@ func Test_fixed_Insert(t *testing.T) {
 
 		tr.Put(1)
 		assertRedBlackProperties(t, tr)
-		assertEqual(t, 1, tr.root.value, "should insert root")
+		assertEqual(t, 1, tr.nodes[tr.root].value, "should insert root")
 
 		tr.Put(22)
 		assertRedBlackProperties(t, tr)
-		assertEqual(t, 22, tr.root.right.value, "should insert child")
+		assertEqual(t, 22, tr.nodes[at(tr, "R")].value, "should insert child")
 
 		tr.Put(27)
 		assertRedBlackProperties(t, tr)
-		assertEqual(t, 22, tr.root.value, "should rotate left")
-		assertEqual(t, 1, tr.root.left.value, "should rotate left")
-		assertEqual(t, 27, tr.root.right.value, "should rotate left")
+		assertEqual(t, 22, tr.nodes[tr.root].value, "should rotate left")
+		assertEqual(t, 1, tr.nodes[at(tr, "L")].value, "should rotate left")
+		assertEqual(t, 27, tr.nodes[at(tr, "R")].value, "should rotate left")
 
 		tr.Put(15)
 		assertRedBlackProperties(t, tr)
-		assertEqual(t, 22, tr.root.value)
-		assertEqual(t, 1, tr.root.left.value)
-		assertEqual(t, 15, tr.root.left.right.value)
+		assertEqual(t, 22, tr.nodes[tr.root].value)
+		assertEqual(t, 1, tr.nodes[at(tr, "L")].value)
+		assertEqual(t, 15, tr.nodes[at(tr, "LR")].value)
 
 		tr.Put(6)
 		assertRedBlackProperties(t, tr)
-		assertEqual(t, 22, tr.root.value)
-		assertEqual(t, 6, tr.root.left.value, "should rotate right then left")
-		assertEqual(t, 1, tr.root.left.left.value, "should rotate right then left")
-		assertEqual(t, 15, tr.root.left.right.value, "should rotate right then left")
-		assertEqual(t, red, tr.root.left.right.color)
+		assertEqual(t, 22, tr.nodes[tr.root].value)
+		assertEqual(t, 6, tr.nodes[at(tr, "L")].value, "should rotate right then left")
+		assertEqual(t, 1, tr.nodes[at(tr, "LL")].value, "should rotate right then left")
+		assertEqual(t, 15, tr.nodes[at(tr, "LR")].value, "should rotate right then left")
+		assertEqual(t, red, tr.nodes[at(tr, "LR")].color)
 
 		tr.Put(11)
 		assertRedBlackProperties(t, tr)
-		assertEqual(t, 22, tr.root.value)
-		assertEqual(t, 6, tr.root.left.value)
-		assertEqual(t, 15, tr.root.left.right.value)
-		assertEqual(t, black, tr.root.left.right.color, "should recolor 15")
-		assertEqual(t, 11, tr.root.left.right.left.value)
+		assertEqual(t, 22, tr.nodes[tr.root].value)
+		assertEqual(t, 6, tr.nodes[at(tr, "L")].value)
+		assertEqual(t, 15, tr.nodes[at(tr, "LR")].value)
+		assertEqual(t, black, tr.nodes[at(tr, "LR")].color, "should recolor 15")
+		assertEqual(t, 11, tr.nodes[at(tr, "LRL")].value)
 
 		tr.Put(17)
 		assertRedBlackProperties(t, tr)
 
 		tr.Put(25)
 		assertRedBlackProperties(t, tr)
-		assertEqual(t, 22, tr.root.value)
-		assertEqual(t, 27, tr.root.right.value)
-		assertEqual(t, 25, tr.root.right.left.value)
+		assertEqual(t, 22, tr.nodes[tr.root].value)
+		assertEqual(t, 27, tr.nodes[at(tr, "R")].value)
+		assertEqual(t, 25, tr.nodes[at(tr, "RL")].value)
 
 		tr.Put(13)
 		assertRedBlackProperties(t, tr)
-		assertEqual(t, 15, tr.root.value, "should rotate 15 up to root")
-		assertEqual(t, 6, tr.root.left.value, "should rotate 15 up to root")
-		assertEqual(t, 1, tr.root.left.left.value, "should rotate 15 up to root")
-		assertEqual(t, 11, tr.root.left.right.value, "should rotate 15 up to root")
-		assertEqual(t, 13, tr.root.left.right.right.value, "should rotate 15 up to root")
-		assertEqual(t, 22, tr.root.right.value, "should rotate 15 up to root")
-		assertEqual(t, 17, tr.root.right.left.value, "should rotate 15 up to root")
-		assertEqual(t, 27, tr.root.right.right.value, "should rotate 15 up to root")
+		assertEqual(t, 15, tr.nodes[tr.root].value, "should rotate 15 up to root")
+		assertEqual(t, 6, tr.nodes[at(tr, "L")].value, "should rotate 15 up to root")
+		assertEqual(t, 1, tr.nodes[at(tr, "LL")].value, "should rotate 15 up to root")
+		assertEqual(t, 11, tr.nodes[at(tr, "LR")].value, "should rotate 15 up to root")
+		assertEqual(t, 13, tr.nodes[at(tr, "LRR")].value, "should rotate 15 up to root")
+		assertEqual(t, 22, tr.nodes[at(tr, "R")].value, "should rotate 15 up to root")
+		assertEqual(t, 17, tr.nodes[at(tr, "RL")].value, "should rotate 15 up to root")
+		assertEqual(t, 27, tr.nodes[at(tr, "RR")].value, "should rotate 15 up to root")
 
 		tr.Put(8)
 		assertRedBlackProperties(t, tr)
 
 		tr.Put(1)
 		assertRedBlackProperties(t, tr)
-		assertEqual(t, 15, tr.root.value)
-		assertEqual(t, 6, tr.root.left.value)
-		assertEqual(t, 1, tr.root.left.left.value)
-		assertEqual(t, 1, tr.root.left.left.right.value, "should insert duplicates to the right")
+		assertEqual(t, 15, tr.nodes[tr.root].value)
+		assertEqual(t, 6, tr.nodes[at(tr, "L")].value)
+		assertEqual(t, 1, tr.nodes[at(tr, "LL")].value)
+		assertEqual(t, 1, tr.nodes[at(tr, "LLR")].value, "should insert duplicates to the right")
 
 		assertEqual(t, 11, tr.Size(), "should reach its capacity")
 	})
@@ -173,172 +202,172 @@ func Test_fixed_Insert(t *testing.T) {
 func Test_fixed_swap(t *testing.T) {
 	t.Run("nil and nil", func(t *testing.T) {
 		tr := Fixed[int](10)
-		tr.swap(nil, nil)
+		tr.swap(nilNode, nilNode)
 		assertEqual(t, 0, tr.Size())
 	})
 
 	t.Run("root with itself", func(t *testing.T) {
 		tr := Fixed[int](10)
-		tr.root = &node[int]{value: 1}
+		tr.root = newNode(tr, 1)
 		tr.swap(tr.root, tr.root)
-		assertEqual(t, 1, tr.root.value)
+		assertEqual(t, 1, tr.nodes[tr.root].value)
 	})
 
 	t.Run("root with nil", func(t *testing.T) {
 		tr := Fixed[int](10)
-		tr.root = &node[int]{value: 1}
-		tr.swap(tr.root, nil)
-		assertEqual(t, 1, tr.root.value)
+		tr.root = newNode(tr, 1)
+		tr.swap(tr.root, nilNode)
+		assertEqual(t, 1, tr.nodes[tr.root].value)
 	})
 
 	t.Run("root with left", func(t *testing.T) {
 		tr := Fixed[int](10)
-		n1 := &node[int]{value: 1}
-		n2 := &node[int]{value: 2}
-		n3 := &node[int]{value: 3}
-		n4 := &node[int]{value: 4}
-		n5 := &node[int]{value: 5}
+		n1 := newNode(tr, 1)
+		n2 := newNode(tr, 2)
+		n3 := newNode(tr, 3)
+		n4 := newNode(tr, 4)
+		n5 := newNode(tr, 5)
 		tr.root = n4
-		n4.setLeft(n2)
-		n4.setRight(n5)
-		n2.setLeft(n1)
-		n2.setRight(n3)
+		tr.nodes.setLeft(n4, n2)
+		tr.nodes.setRight(n4, n5)
+		tr.nodes.setLeft(n2, n1)
+		tr.nodes.setRight(n2, n3)
 
-		tr.swap(tr.root, tr.root.left)
+		tr.swap(tr.root, at(tr, "L"))
 		assertEqual(t, n2, tr.root)
-		assertEqual(t, n4, tr.root.left)
-		assertEqual(t, n1, tr.root.left.left)
-		assertEqual(t, n3, tr.root.left.right)
-		assertEqual(t, n5, tr.root.right)
+		assertEqual(t, n4, at(tr, "L"))
+		assertEqual(t, n1, at(tr, "LL"))
+		assertEqual(t, n3, at(tr, "LR"))
+		assertEqual(t, n5, at(tr, "R"))
 	})
 
 	t.Run("root with right", func(t *testing.T) {
 		tr := Fixed[int](10)
-		n1 := &node[int]{value: 1}
-		n2 := &node[int]{value: 2}
-		n3 := &node[int]{value: 3}
-		n4 := &node[int]{value: 4}
-		n5 := &node[int]{value: 5}
+		n1 := newNode(tr, 1)
+		n2 := newNode(tr, 2)
+		n3 := newNode(tr, 3)
+		n4 := newNode(tr, 4)
+		n5 := newNode(tr, 5)
 		tr.root = n2
-		n2.setLeft(n1)
-		n2.setRight(n4)
-		n4.setLeft(n3)
-		n4.setRight(n5)
+		tr.nodes.setLeft(n2, n1)
+		tr.nodes.setRight(n2, n4)
+		tr.nodes.setLeft(n4, n3)
+		tr.nodes.setRight(n4, n5)
 
-		tr.swap(tr.root, tr.root.right)
+		tr.swap(tr.root, at(tr, "R"))
 		assertEqual(t, n4, tr.root)
-		assertEqual(t, n1, tr.root.left)
-		assertEqual(t, n2, tr.root.right)
-		assertEqual(t, n3, tr.root.right.left)
-		assertEqual(t, n5, tr.root.right.right)
+		assertEqual(t, n1, at(tr, "L"))
+		assertEqual(t, n2, at(tr, "R"))
+		assertEqual(t, n3, at(tr, "RL"))
+		assertEqual(t, n5, at(tr, "RR"))
 	})
 
 	t.Run("root with left left", func(t *testing.T) {
 		tr := Fixed[int](10)
-		n1 := &node[int]{value: 1}
-		n2 := &node[int]{value: 2}
-		n3 := &node[int]{value: 3}
-		n4 := &node[int]{value: 4}
-		n5 := &node[int]{value: 5}
-		n6 := &node[int]{value: 6}
-		n7 := &node[int]{value: 7}
+		n1 := newNode(tr, 1)
+		n2 := newNode(tr, 2)
+		n3 := newNode(tr, 3)
+		n4 := newNode(tr, 4)
+		n5 := newNode(tr, 5)
+		n6 := newNode(tr, 6)
+		n7 := newNode(tr, 7)
 		tr.root = n6
-		n6.setLeft(n4)
-		n6.setRight(n7)
-		n4.setLeft(n2)
-		n4.setRight(n5)
-		n2.setLeft(n1)
-		n2.setRight(n3)
-
-		tr.swap(tr.root, tr.root.left.left)
+		tr.nodes.setLeft(n6, n4)
+		tr.nodes.setRight(n6, n7)
+		tr.nodes.setLeft(n4, n2)
+		tr.nodes.setRight(n4, n5)
+		tr.nodes.setLeft(n2, n1)
+		tr.nodes.setRight(n2, n3)
+
+		tr.swap(tr.root, at(tr, "LL"))
 		assertEqual(t, n2, tr.root)
-		assertEqual(t, n4, tr.root.left)
-		assertEqual(t, n7, tr.root.right)
-		assertEqual(t, n6, tr.root.left.left)
-		assertEqual(t, n5, tr.root.left.right)
-		assertEqual(t, n1, tr.root.left.left.left)
-		assertEqual(t, n3, tr.root.left.left.right)
+		assertEqual(t, n4, at(tr, "L"))
+		assertEqual(t, n7, at(tr, "R"))
+		assertEqual(t, n6, at(tr, "LL"))
+		assertEqual(t, n5, at(tr, "LR"))
+		assertEqual(t, n1, at(tr, "LLL"))
+		assertEqual(t, n3, at(tr, "LLR"))
 	})
 
 	t.Run("root with left right", func(t *testing.T) {
 		tr := Fixed[int](10)
-		n1 := &node[int]{value: 1}
-		n2 := &node[int]{value: 2}
-		n3 := &node[int]{value: 3}
-		n4 := &node[int]{value: 4}
-		n5 := &node[int]{value: 5}
-		n6 := &node[int]{value: 6}
-		n7 := &node[int]{value: 7}
+		n1 := newNode(tr, 1)
+		n2 := newNode(tr, 2)
+		n3 := newNode(tr, 3)
+		n4 := newNode(tr, 4)
+		n5 := newNode(tr, 5)
+		n6 := newNode(tr, 6)
+		n7 := newNode(tr, 7)
 		tr.root = n6
-		n6.setLeft(n4)
-		n6.setRight(n7)
-		n4.setLeft(n2)
-		n4.setRight(n5)
-		n2.setLeft(n1)
-		n2.setRight(n3)
-
-		tr.swap(tr.root, tr.root.left.right)
+		tr.nodes.setLeft(n6, n4)
+		tr.nodes.setRight(n6, n7)
+		tr.nodes.setLeft(n4, n2)
+		tr.nodes.setRight(n4, n5)
+		tr.nodes.setLeft(n2, n1)
+		tr.nodes.setRight(n2, n3)
+
+		tr.swap(tr.root, at(tr, "LR"))
 		assertEqual(t, n5, tr.root)
-		assertEqual(t, n4, tr.root.left)
-		assertEqual(t, n7, tr.root.right)
-		assertEqual(t, n2, tr.root.left.left)
-		assertEqual(t, n6, tr.root.left.right)
-		assertEqual(t, n1, tr.root.left.left.left)
-		assertEqual(t, n3, tr.root.left.left.right)
+		assertEqual(t, n4, at(tr, "L"))
+		assertEqual(t, n7, at(tr, "R"))
+		assertEqual(t, n2, at(tr, "LL"))
+		assertEqual(t, n6, at(tr, "LR"))
+		assertEqual(t, n1, at(tr, "LLL"))
+		assertEqual(t, n3, at(tr, "LLR"))
 	})
 
 	t.Run("left with grandchild", func(t *testing.T) {
 		tr := Fixed[int](10)
-		n1 := &node[int]{value: 1}
-		n2 := &node[int]{value: 2}
-		n3 := &node[int]{value: 3}
-		n4 := &node[int]{value: 4}
-		n5 := &node[int]{value: 5}
-		n6 := &node[int]{value: 6}
-		n7 := &node[int]{value: 7}
+		n1 := newNode(tr, 1)
+		n2 := newNode(tr, 2)
+		n3 := newNode(tr, 3)
+		n4 := newNode(tr, 4)
+		n5 := newNode(tr, 5)
+		n6 := newNode(tr, 6)
+		n7 := newNode(tr, 7)
 		tr.root = n6
-		n6.setLeft(n4)
-		n6.setRight(n7)
-		n4.setLeft(n2)
-		n4.setRight(n5)
-		n2.setLeft(n1)
-		n2.setRight(n3)
+		tr.nodes.setLeft(n6, n4)
+		tr.nodes.setRight(n6, n7)
+		tr.nodes.setLeft(n4, n2)
+		tr.nodes.setRight(n4, n5)
+		tr.nodes.setLeft(n2, n1)
+		tr.nodes.setRight(n2, n3)
 
 		tr.swap(n4, n1)
 		assertEqual(t, n6, tr.root)
-		assertEqual(t, n1, tr.root.left)
-		assertEqual(t, n7, tr.root.right)
-		assertEqual(t, n2, tr.root.left.left)
-		assertEqual(t, n5, tr.root.left.right)
-		assertEqual(t, n4, tr.root.left.left.left)
-		assertEqual(t, n3, tr.root.left.left.right)
+		assertEqual(t, n1, at(tr, "L"))
+		assertEqual(t, n7, at(tr, "R"))
+		assertEqual(t, n2, at(tr, "LL"))
+		assertEqual(t, n5, at(tr, "LR"))
+		assertEqual(t, n4, at(tr, "LLL"))
+		assertEqual(t, n3, at(tr, "LLR"))
 	})
 
 	t.Run("right with grandchild", func(t *testing.T) {
 		tr := Fixed[int](10)
-		n1 := &node[int]{value: 1}
-		n2 := &node[int]{value: 2}
-		n3 := &node[int]{value: 3}
-		n4 := &node[int]{value: 4}
-		n5 := &node[int]{value: 5}
-		n6 := &node[int]{value: 6}
-		n7 := &node[int]{value: 7}
+		n1 := newNode(tr, 1)
+		n2 := newNode(tr, 2)
+		n3 := newNode(tr, 3)
+		n4 := newNode(tr, 4)
+		n5 := newNode(tr, 5)
+		n6 := newNode(tr, 6)
+		n7 := newNode(tr, 7)
 		tr.root = n2
-		n2.setLeft(n1)
-		n2.setRight(n4)
-		n4.setLeft(n3)
-		n4.setRight(n6)
-		n6.setLeft(n5)
-		n6.setRight(n7)
+		tr.nodes.setLeft(n2, n1)
+		tr.nodes.setRight(n2, n4)
+		tr.nodes.setLeft(n4, n3)
+		tr.nodes.setRight(n4, n6)
+		tr.nodes.setLeft(n6, n5)
+		tr.nodes.setRight(n6, n7)
 
 		tr.swap(n4, n7)
 		assertEqual(t, n2, tr.root)
-		assertEqual(t, n1, tr.root.left)
-		assertEqual(t, n7, tr.root.right)
-		assertEqual(t, n3, tr.root.right.left)
-		assertEqual(t, n6, tr.root.right.right)
-		assertEqual(t, n5, tr.root.right.right.left)
-		assertEqual(t, n4, tr.root.right.right.right)
+		assertEqual(t, n1, at(tr, "L"))
+		assertEqual(t, n7, at(tr, "R"))
+		assertEqual(t, n3, at(tr, "RL"))
+		assertEqual(t, n6, at(tr, "RR"))
+		assertEqual(t, n5, at(tr, "RRL"))
+		assertEqual(t, n4, at(tr, "RRR"))
 	})
 }
 
@@ -346,120 +375,120 @@ func Test_fixed_delete(t *testing.T) {
 	t.Run("remove leaf, no rotate", func(t *testing.T) {
 		tr := makeFixed(1, 22, 27, 15, 6, 11, 17, 25, 13, 8, 1)
 
-		p := tr.root.left.left
-		assertEqual(t, 1, p.value)
-		n := p.right
-		assertEqual(t, 1, n.value)
+		p := at(tr, "LL")
+		assertEqual(t, 1, tr.nodes[p].value)
+		n := tr.nodes[p].right
+		assertEqual(t, 1, tr.nodes[n].value)
 		tr.delete(n)
 		assertEqual(t, 10, tr.Size())
 		assertRedBlackProperties(t, tr)
-		assertNil(t, n.parent)
-		assertNil(t, n.left)
-		assertNil(t, n.right)
-		assertNil(t, p.left)
-		assertNil(t, p.right)
+		assertEqual(t, nilNode, tr.nodes[n].parent)
+		assertEqual(t, nilNode, tr.nodes[n].left)
+		assertEqual(t, nilNode, tr.nodes[n].right)
+		assertEqual(t, nilNode, tr.nodes[p].left)
+		assertEqual(t, nilNode, tr.nodes[p].right)
 	})
 
 	t.Run("replace parent with child; case 4", func(t *testing.T) {
 		tr := makeFixed(1, 22, 27, 15, 6, 11, 17, 25, 13, 8, 1)
 
-		p := tr.root.right
-		assertEqual(t, 22, p.value)
-		n := p.right
-		assertEqual(t, 27, n.value)
+		p := at(tr, "R")
+		assertEqual(t, 22, tr.nodes[p].value)
+		n := tr.nodes[p].right
+		assertEqual(t, 27, tr.nodes[n].value)
 		tr.delete(n)
 		assertEqual(t, 10, tr.Size())
 		assertRedBlackProperties(t, tr)
-		assertNil(t, n.parent)
-		assertNil(t, n.left)
-		assertNil(t, n.right)
-		assertEqual(t, 17, p.left.value)
-		assertEqual(t, 25, p.right.value)
+		assertEqual(t, nilNode, tr.nodes[n].parent)
+		assertEqual(t, nilNode, tr.nodes[n].left)
+		assertEqual(t, nilNode, tr.nodes[n].right)
+		assertEqual(t, 17, tr.nodes[at(tr, "RL")].value)
+		assertEqual(t, 25, tr.nodes[at(tr, "RR")].value)
 	})
 
 	t.Run("remove parent with two children; cases 5 right and 6 left", func(t *testing.T) {
 		tr := makeFixed(1, 22, 27, 15, 6, 11, 17, 25, 13, 8, 1)
 
 		p := tr.root
-		assertEqual(t, 15, p.value)
-		n := p.right
-		assertEqual(t, 22, n.value)
+		assertEqual(t, 15, tr.nodes[p].value)
+		n := tr.nodes[p].right
+		assertEqual(t, 22, tr.nodes[n].value)
 		tr.delete(n)
 		assertEqual(t, 10, tr.Size())
 		assertRedBlackProperties(t, tr)
-		assertNil(t, n.parent)
-		assertNil(t, n.left)
-		assertNil(t, n.right)
+		assertEqual(t, nilNode, tr.nodes[n].parent)
+		assertEqual(t, nilNode, tr.nodes[n].left)
+		assertEqual(t, nilNode, tr.nodes[n].right)
 		assertEqual(t, tr.root, p, "should keep 15 at root")
-		assertEqual(t, 25, p.right.value)
-		assertEqual(t, 17, p.right.left.value)
-		assertEqual(t, 27, p.right.right.value)
+		assertEqual(t, 25, tr.nodes[at(tr, "R")].value)
+		assertEqual(t, 17, tr.nodes[at(tr, "RL")].value)
+		assertEqual(t, 27, tr.nodes[at(tr, "RR")].value)
 	})
 
 	t.Run("case 3 rotate left", func(t *testing.T) {
 		tr := makeFixed(5, 8, 1, 7, 9, 6)
 
 		p := tr.root
-		assertEqual(t, 5, p.value)
-		n := p.left
-		assertEqual(t, 1, n.value)
+		assertEqual(t, 5, tr.nodes[p].value)
+		n := tr.nodes[p].left
+		assertEqual(t, 1, tr.nodes[n].value)
 		tr.delete(n)
 		assertRedBlackProperties(t, tr)
-		assertNil(t, n.parent)
-		assertNil(t, n.left)
-		assertNil(t, n.right)
-		assertEqual(t, 8, tr.root.value)
-		assertEqual(t, 6, tr.root.left.value)
-		assertEqual(t, 9, tr.root.right.value)
-		assertEqual(t, 5, tr.root.left.left.value)
-		assertEqual(t, 7, tr.root.left.right.value)
+		assertEqual(t, nilNode, tr.nodes[n].parent)
+		assertEqual(t, nilNode, tr.nodes[n].left)
+		assertEqual(t, nilNode, tr.nodes[n].right)
+		assertEqual(t, 8, tr.nodes[tr.root].value)
+		assertEqual(t, 6, tr.nodes[at(tr, "L")].value)
+		assertEqual(t, 9, tr.nodes[at(tr, "R")].value)
+		assertEqual(t, 5, tr.nodes[at(tr, "LL")].value)
+		assertEqual(t, 7, tr.nodes[at(tr, "LR")].value)
 	})
 
 	t.Run("case 3 rotate right", func(t *testing.T) {
 		tr := makeFixed(5, 8, 2, 1, 3, 4)
 
 		p := tr.root
-		assertEqual(t, 5, p.value)
-		n := p.right
-		assertEqual(t, 8, n.value)
+		assertEqual(t, 5, tr.nodes[p].value)
+		n := tr.nodes[p].right
+		assertEqual(t, 8, tr.nodes[n].value)
 		tr.delete(n)
 		assertRedBlackProperties(t, tr)
-		assertNil(t, n.parent)
-		assertNil(t, n.left)
-		assertNil(t, n.right)
-		assertEqual(t, 2, tr.root.value)
-		assertEqual(t, 1, tr.root.left.value)
-		assertEqual(t, 4, tr.root.right.value)
-		assertEqual(t, 3, tr.root.right.left.value)
-		assertEqual(t, 5, tr.root.right.right.value)
+		assertEqual(t, nilNode, tr.nodes[n].parent)
+		assertEqual(t, nilNode, tr.nodes[n].left)
+		assertEqual(t, nilNode, tr.nodes[n].right)
+		assertEqual(t, 2, tr.nodes[tr.root].value)
+		assertEqual(t, 1, tr.nodes[at(tr, "L")].value)
+		assertEqual(t, 4, tr.nodes[at(tr, "R")].value)
+		assertEqual(t, 3, tr.nodes[at(tr, "RL")].value)
+		assertEqual(t, 5, tr.nodes[at(tr, "RR")].value)
 	})
 
 	t.Run("case 2", func(t *testing.T) {
 		tr := makeFixed(5, 2, 8, 6)
 
 		p := tr.root
-		assertEqual(t, 5, p.value)
-		n := p.left
-		assertEqual(t, 2, n.value)
+		assertEqual(t, 5, tr.nodes[p].value)
+		n := tr.nodes[p].left
+		assertEqual(t, 2, tr.nodes[n].value)
 
 		// Delete the 6 to get the tree in the correct state
-		n6 := p.right.left
-		assertEqual(t, 6, n6.value)
+		n6 := at(tr, "RL")
+		assertEqual(t, 6, tr.nodes[n6].value)
 		tr.delete(n6)
 
-		assertEqual(t, black, p.color)
-		assertEqual(t, black, n.color)
-		assertEqual(t, black, p.right.color)
+		assertEqual(t, black, tr.nodes[p].color)
+		assertEqual(t, black, tr.nodes[n].color)
+		assertEqual(t, black, tr.nodes[at(tr, "R")].color)
 
 		// Now it will trigger delete case 2
 		tr.delete(n)
 		assertRedBlackProperties(t, tr)
-		assertNil(t, n.parent)
-		assertNil(t, n.left)
-		assertNil(t, n.right)
-		assertEqual(t, 5, tr.root.value)
-		assertNil(t, tr.root.left)
-		assertEqual(t, 8, tr.root.right.value)
+		assertEqual(t, nilNode, tr.nodes[n].parent)
+		assertEqual(t, nilNode, tr.nodes[n].left)
+		assertEqual(t, nilNode, tr.nodes[n].right)
+		assertEqual(t, 5, tr.nodes[tr.root].value)
+		assertEqual(t, nilNode, at(tr, "L"))
+		assertEqual(t, 8, tr.nodes[at(tr, "R")].value)
 	})
 }
 
@@ -468,39 +497,50 @@ func Test_fixed_rollingWindowAtCapacity(t *testing.T) {
 		tr := Fixed[int](1)
 		tr.Put(1)
 		assertEqual(t, 1, tr.Size())
-		assertEqual(t, 1, tr.root.value)
+		assertEqual(t, 1, tr.nodes[tr.root].value)
 		tr.Put(2)
 		assertEqual(t, 1, tr.Size())
-		assertEqual(t, 2, tr.root.value, "should replace existing value")
+		assertEqual(t, 2, tr.nodes[tr.root].value, "should replace existing value")
+	})
+
+	t.Run("two nodes replace root with red child", func(t *testing.T) {
+		tr := makeFixed(1, 2)
+		assertEqual(t, red, tr.nodes[at(tr, "R")].color)
+
+		tr.Put(0)
+		assertRedBlackProperties(t, tr)
+		assertEqual(t, black, tr.nodes[tr.root].color, "should recolor the red child that replaced the root")
+		assertEqual(t, 2, tr.nodes[tr.root].value)
+		assertEqual(t, 0, tr.nodes[at(tr, "L")].value)
 	})
 
 	t.Run("three nodes", func(t *testing.T) {
 		tr := makeFixed(1, 2, 3)
 		assertEqual(t, 3, tr.Size())
-		assertEqual(t, 2, tr.root.value)
-		assertEqual(t, 1, tr.root.left.value)
-		assertEqual(t, 3, tr.root.right.value)
+		assertEqual(t, 2, tr.nodes[tr.root].value)
+		assertEqual(t, 1, tr.nodes[at(tr, "L")].value)
+		assertEqual(t, 3, tr.nodes[at(tr, "R")].value)
 
 		tr.Put(4)
 		assertEqual(t, 3, tr.Size(), "should replace oldest value")
-		assertEqual(t, 3, tr.root.value)
-		assertEqual(t, 2, tr.root.left.value)
-		assertEqual(t, 4, tr.root.right.value)
+		assertEqual(t, 3, tr.nodes[tr.root].value)
+		assertEqual(t, 2, tr.nodes[at(tr, "L")].value)
+		assertEqual(t, 4, tr.nodes[at(tr, "R")].value)
 	})
 
 	t.Run("three nodes replace root", func(t *testing.T) {
 		tr := makeFixed(3, 1, 5)
 		assertEqual(t, 3, tr.Size())
-		assertEqual(t, 3, tr.root.value)
-		assertEqual(t, 1, tr.root.left.value)
-		assertEqual(t, 5, tr.root.right.value)
+		assertEqual(t, 3, tr.nodes[tr.root].value)
+		assertEqual(t, 1, tr.nodes[at(tr, "L")].value)
+		assertEqual(t, 5, tr.nodes[at(tr, "R")].value)
 
 		tr.Put(4)
 		assertEqual(t, 3, tr.Size(), "should replace oldest value at root")
-		assertEqual(t, 4, tr.root.value)
-		assertNil(t, tr.root.parent)
-		assertEqual(t, 1, tr.root.left.value)
-		assertEqual(t, 5, tr.root.right.value)
+		assertEqual(t, 4, tr.nodes[tr.root].value)
+		assertEqual(t, nilNode, tr.nodes[tr.root].parent)
+		assertEqual(t, 1, tr.nodes[at(tr, "L")].value)
+		assertEqual(t, 5, tr.nodes[at(tr, "R")].value)
 	})
 
 	t.Run("resets subtree counts for replaced node", func(t *testing.T) {
@@ -663,7 +703,7 @@ func Test_fixed_MeanVariance(t *testing.T) {
 				tss += delta * delta
 			}
 
-			expectedVar := tss / float64(tr.size)
+			expectedVar := tss / float64(tr.Size())
 
 			// expectedVar can be rather large, so the allowed error delta is adjusted accordingly
 			if !assertInDelta(t, expectedMean, tr.Mean(), 1e-6, "mean should be within error delta") ||
@@ -809,3 +849,620 @@ func Test_slowQuantile(t *testing.T) {
 	assertEqual(t, 16, slowQuantile(v, 0.75))
 	assertEqual(t, 21, slowQuantile(v, 1.0))
 }
+
+func Test_fixed_IQR(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		tr := Fixed[int](1)
+		assertEqual(t, 0, tr.IQR())
+	})
+
+	t.Run("ten nodes", func(t *testing.T) {
+		tr := makeFixed(3, 6, 7, 8, 8, 10, 13, 15, 16, 20)
+		assertEqual(t, 8, tr.IQR())
+	})
+}
+
+func Test_fixed_IsOutlier(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		tr := Fixed[int](1)
+		low, high := tr.IsOutlier(100, 1.5)
+		assertEqual(t, false, low)
+		assertEqual(t, false, high)
+	})
+
+	t.Run("fences", func(t *testing.T) {
+		// Q1 = 7, Q3 = 15, IQR = 8, so the fences are -5 and 27
+		tr := makeFixed(3, 6, 7, 8, 8, 10, 13, 15, 16, 20)
+		cases := []struct {
+			v         int
+			low, high bool
+		}{
+			{v: -6, low: true},
+			{v: -5},
+			{v: 10},
+			{v: 27},
+			{v: 28, high: true},
+		}
+
+		for _, c := range cases {
+			low, high := tr.IsOutlier(c.v, 1.5)
+			assertEqual(t, c.low, low, "unexpected low outlier classification")
+			assertEqual(t, c.high, high, "unexpected high outlier classification")
+		}
+	})
+}
+
+func Test_fixed_WithOutlierRejection(t *testing.T) {
+	t.Run("accepts all values until at capacity", func(t *testing.T) {
+		tr := Fixed[int](3, WithOutlierRejection(1.5))
+		tr.Put(1)
+		tr.Put(1)
+		tr.Put(1000)
+		assertEqual(t, 3, tr.Size())
+		assertEqual(t, 1000, tr.Max())
+		assertEqual(t, 0, tr.Rejected())
+	})
+
+	t.Run("rejects outliers at capacity", func(t *testing.T) {
+		tr := Fixed[int](10, WithOutlierRejection(1.5))
+		for _, v := range []int{3, 6, 7, 8, 8, 10, 13, 15, 16, 20} {
+			tr.Put(v)
+		}
+
+		tr.Put(28)
+		assertEqual(t, 1, tr.Rejected())
+		assertEqual(t, 20, tr.Max(), "should not add the outlier")
+		assertEqual(t, 3, tr.Min(), "should not evict the oldest value")
+		assertRedBlackProperties(t, tr)
+
+		tr.Put(27)
+		assertEqual(t, 1, tr.Rejected())
+		assertEqual(t, 27, tr.Max(), "should add a value on the fence")
+		assertEqual(t, 6, tr.Min(), "should evict the oldest value")
+	})
+
+	t.Run("rejects a long run of outliers", func(t *testing.T) {
+		tr := Fixed[int](10, WithOutlierRejection(1.5))
+		for range 10 {
+			tr.Put(5)
+		}
+
+		for range 100 {
+			tr.Put(0)
+		}
+		assertEqual(t, 100, tr.Rejected())
+		assertEqual(t, 5, tr.Min(), "should keep the old level")
+	})
+
+	t.Run("follows a level shift", func(t *testing.T) {
+		tr := Fixed[int](10, WithOutlierRejection(1.5), WithOutlierReadmission(5))
+		for range 10 {
+			tr.Put(5)
+		}
+
+		for range 100 {
+			tr.Put(6)
+		}
+		assertEqual(t, 5, tr.Rejected(), "should only reject the start of the shift")
+		assertInDelta(t, 6.0, tr.Mean(), 1e-9)
+		assertEqual(t, 6, tr.Min())
+		assertRedBlackProperties(t, tr)
+
+		tr.Put(5)
+		assertEqual(t, 6, tr.Rejected(), "should reject outliers again after the shift")
+	})
+
+	t.Run("single value capacity", func(t *testing.T) {
+		tr := Fixed[int](1, WithOutlierRejection(1.5))
+		tr.Put(1)
+		tr.Put(2)
+		tr.Put(3)
+		assertEqual(t, 2, tr.Rejected())
+		assertEqual(t, 1, tr.Max())
+
+		tr = Fixed[int](1, WithOutlierRejection(1.5), WithOutlierReadmission(1))
+		tr.Put(1)
+		tr.Put(2)
+		tr.Put(3)
+		assertEqual(t, 1, tr.Rejected())
+		assertEqual(t, 3, tr.Max())
+	})
+}
+
+func Test_fixed_Reset(t *testing.T) {
+	tr := makeFixed(5, 1, 3)
+	tr.Reset()
+	assertEqual(t, 0, tr.Size())
+	assertEqual(t, 0, tr.Min())
+	assertEqual(t, 0, tr.Max())
+	assertEqual(t, 0.0, tr.Mean())
+	assertEqual(t, 0.0, tr.Variance())
+	assertEqual(t, 0, tr.Quantile(0.5))
+
+	tr.Put(2)
+	tr.Put(4)
+	assertRedBlackProperties(t, tr)
+	assertEqual(t, 2, tr.Size())
+	assertEqual(t, 2, tr.Min())
+	assertEqual(t, 4, tr.Max())
+	assertEqual(t, 3.0, tr.Mean())
+}
+
+func Test_fixed_WithStats(t *testing.T) {
+	t.Run("mean only", func(t *testing.T) {
+		tr := Fixed[int](3, WithStats(Mean))
+		assertEqual(t, true, tr.nodes == nil, "should not allocate a tree")
+		for _, v := range []int{1, 2, 3, 4, 5} {
+			tr.Put(v)
+		}
+		assertEqual(t, 3, tr.Size())
+		assertEqual(t, 4.0, tr.Mean())
+		assertEqual(t, 2.0/3.0, tr.Variance())
+		assertEqual(t, 0, tr.Min(), "should not track min")
+		assertEqual(t, 0, tr.Max(), "should not track max")
+		assertEqual(t, 0, tr.Quantile(0.5), "should not track quantiles")
+	})
+
+	t.Run("min max rolling 50 random", func(t *testing.T) {
+		const size = 50
+		values := make([]int, 0, size)
+		tr := Fixed[int](size, WithStats(MinMax))
+		assertEqual(t, true, tr.nodes == nil, "should not allocate a tree")
+		for i := 0; i < 1000; i++ {
+			v := rand.IntN(100)
+			if i >= size {
+				values[i%size] = v
+			} else {
+				values = append(values, v)
+			}
+
+			tr.Put(v)
+			if !assertEqual(t, slices.Min(values), tr.Min(), "min should match") ||
+				!assertEqual(t, slices.Max(values), tr.Max(), "max should match") {
+				break
+			}
+		}
+	})
+
+	t.Run("quantiles allocates a tree", func(t *testing.T) {
+		tr := Fixed[int](3, WithStats(Quantiles))
+		assertEqual(t, true, tr.nodes != nil)
+		tr.Put(3)
+		tr.Put(1)
+		tr.Put(2)
+		assertEqual(t, 2, tr.Quantile(0.5))
+		assertRedBlackProperties(t, tr)
+	})
+
+	t.Run("outlier rejection implies quantiles", func(t *testing.T) {
+		tr := Fixed[int](3, WithStats(Mean), WithOutlierRejection(1.5))
+		assertEqual(t, true, tr.nodes != nil)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		tr := Fixed[int](2, WithStats(MinMax))
+		tr.Put(5)
+		tr.Put(7)
+		tr.Reset()
+		assertEqual(t, 0, tr.Size())
+		assertEqual(t, 0, tr.Min())
+		assertEqual(t, 0.0, tr.Mean())
+
+		tr.Put(3)
+		tr.Put(1)
+		tr.Put(2)
+		assertEqual(t, 1, tr.Min())
+		assertEqual(t, 2, tr.Max())
+	})
+}
+
+// assertSameWindow compares the statistics of two windows that should hold the same values.
+func assertSameWindow(t *testing.T, expected, actual *FixedWindow[int]) bool {
+	ok := assertEqual(t, expected.Size(), actual.Size(), "size should match") &&
+		assertEqual(t, expected.Min(), actual.Min(), "min should match") &&
+		assertEqual(t, expected.Max(), actual.Max(), "max should match") &&
+		assertInDelta(t, expected.Mean(), actual.Mean(), 1e-9, "mean should match") &&
+		assertInDelta(t, expected.Variance(), actual.Variance(), 1e-6, "variance should match")
+	for q := 0.0; ok && q <= 1.0; q += 0.05 {
+		ok = assertEqual(t, expected.Quantile(q), actual.Quantile(q), "quantile should match")
+	}
+	return ok
+}
+
+// checkRandomPuts runs a subtest for each of several capacities, which Puts the same
+// random values into a reference FixedWindow and into whatever setup creates around it.
+// The values are in a narrow range, which produces many equal values. After each Put,
+// the subtest stops if check reports a mismatch.
+func checkRandomPuts(t *testing.T, setup func(ref *FixedWindow[int]) (put func(v int), check func(t *testing.T) bool)) {
+	for _, capacity := range []int{1, 2, 7, 50} {
+		t.Run(fmt.Sprint(capacity), func(t *testing.T) {
+			ref := Fixed[int](capacity)
+			put, check := setup(ref)
+			for i := range 1000 {
+				v := rand.IntN(20)
+				ref.Put(v)
+				if put != nil {
+					put(v)
+				}
+				if !check(t) {
+					t.Fatalf("mismatch after put %d", i)
+				}
+			}
+		})
+	}
+}
+
+func Test_fixed_PutAll(t *testing.T) {
+	for _, capacity := range []int{1, 2, 3, 7, 8, 50} {
+		for _, batch := range []int{0, 1, capacity - 1, capacity, capacity + 1, 3 * capacity} {
+			for _, sorted := range []bool{false, true} {
+				values := make([]int, max(batch, 0))
+				for i := range values {
+					values[i] = rand.IntN(20)
+				}
+				if sorted {
+					slices.Sort(values)
+				}
+
+				expected := Fixed[int](capacity)
+				actual := Fixed[int](capacity)
+				for _, v := range []int{5, 15} {
+					expected.Put(v)
+					actual.Put(v)
+				}
+
+				for _, v := range values {
+					expected.Put(v)
+				}
+				actual.PutAll(values)
+				if !assertRedBlackProperties(t, actual) || !assertSameWindow(t, expected, actual) {
+					t.Fatalf("capacity %d, batch %d, sorted %v", capacity, batch, sorted)
+				}
+
+				// The ring should evict the oldest loaded values first
+				for i := 0; i < 2*capacity; i++ {
+					v := rand.IntN(20)
+					expected.Put(v)
+					actual.Put(v)
+					if !assertRedBlackProperties(t, actual) || !assertSameWindow(t, expected, actual) {
+						t.Fatalf("capacity %d, batch %d, sorted %v, put %d", capacity, batch, sorted, i)
+					}
+				}
+			}
+		}
+	}
+}
+
+func Test_fixed_PutSeq(t *testing.T) {
+	for _, n := range []int{3, 10, 11, 25} {
+		values := make([]int, n)
+		for i := range values {
+			values[i] = rand.IntN(100)
+		}
+
+		expected := Fixed[int](10)
+		expected.PutAll(values)
+		actual := Fixed[int](10)
+		actual.PutSeq(slices.Values(values))
+		assertRedBlackProperties(t, actual)
+		assertSameWindow(t, expected, actual)
+
+		expected.Put(50)
+		actual.Put(50)
+		assertSameWindow(t, expected, actual)
+	}
+
+	// A short sequence shouldn't allocate a buffer for the whole capacity
+	tr := Fixed[int](1 << 20)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	tr.PutSeq(slices.Values([]int{1, 2}))
+	runtime.ReadMemStats(&after)
+	assertEqual(t, 2, tr.Size())
+	assertLessOrEqual(t, after.TotalAlloc-before.TotalAlloc, 1<<10)
+}
+
+func Test_fixed_Quantiles(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		tr := Fixed[int](3)
+		dst, err := tr.Quantiles(nil, 0.5, 0.9)
+		assertNil(t, err)
+		assertEqual(t, true, slices.Equal([]int{0, 0}, dst))
+	})
+
+	t.Run("appends to dst", func(t *testing.T) {
+		tr := makeFixed(3, 6, 7, 8, 8, 10, 13, 15, 16, 20)
+		dst, err := tr.Quantiles([]int{-1}, 0.75, 0.0, 0.5, 0.5, 1.0)
+		assertNil(t, err)
+		assertEqual(t, true, slices.Equal([]int{-1, 15, 3, 8, 8, 20}, dst))
+	})
+
+	t.Run("does not allocate with capacity", func(t *testing.T) {
+		tr := makeFixed(3, 6, 7, 8, 8, 10, 13, 15, 16, 20)
+		dst := make([]int, 0, 6)
+		allocs := testing.AllocsPerRun(10, func() {
+			dst, _ = tr.Quantiles(dst[:0], 0.1, 0.25, 0.5, 0.75, 0.9, 0.99)
+		})
+		assertEqual(t, 0.0, allocs)
+	})
+
+	t.Run("invalid quantile", func(t *testing.T) {
+		tr := makeFixed(1, 2, 3)
+		dst, err := tr.Quantiles([]int{7}, 0.5, 1.5)
+		assertEqual(t, true, errors.Is(err, ErrInvalidQuantile))
+		assertEqual(t, true, slices.Equal([]int{7}, dst), "should not modify dst")
+	})
+
+	t.Run("without quantiles", func(t *testing.T) {
+		tr := Fixed[int](3, WithStats(Mean))
+		tr.Put(1)
+		dst, err := tr.Quantiles(nil, 0.5)
+		assertNil(t, err)
+		assertEqual(t, true, slices.Equal([]int{0}, dst))
+	})
+
+	for _, k := range []int{1, 6, 16, 40} {
+		t.Run("matches quantile", func(t *testing.T) {
+			tr := Fixed[int](50)
+			qs := make([]float64, k)
+			for i := 0; i < 200; i++ {
+				tr.Put(rand.IntN(100))
+				for j := range qs {
+					qs[j] = rand.Float64()
+				}
+
+				dst, err := tr.Quantiles(nil, qs...)
+				assertNil(t, err)
+				for j, q := range qs {
+					if !assertEqual(t, tr.Quantile(q), dst[j], "quantile should match") {
+						t.Fatalf("k %d, put %d, q %v", k, i, q)
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_NewFixed(t *testing.T) {
+	for _, capacity := range []int{-1, 0, math.MaxInt32} {
+		tr, err := NewFixed[int](capacity)
+		assertEqual(t, true, tr == nil)
+		assertEqual(t, true, errors.Is(err, ErrInvalidCapacity))
+	}
+
+	tr, err := NewFixed[int](1)
+	assertNil(t, err)
+	tr.Put(1)
+	assertEqual(t, 1, tr.Size())
+
+	defer func() {
+		assertEqual(t, true, errors.Is(recover().(error), ErrInvalidCapacity), "Fixed should panic")
+	}()
+	Fixed[int](0)
+}
+
+func Test_fixed_QuantileE(t *testing.T) {
+	tr := makeFixed(3, 1, 2)
+	v, err := tr.QuantileE(0.5)
+	assertNil(t, err)
+	assertEqual(t, 2, v)
+
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		_, err = tr.QuantileE(q)
+		assertEqual(t, true, errors.Is(err, ErrInvalidQuantile))
+	}
+}
+
+func Test_fixed_Kth(t *testing.T) {
+	tr := makeFixed(8, 3, 5, 1, 5)
+	for k, want := range []int{1, 3, 5, 5, 8} {
+		v, ok := tr.Kth(k + 1)
+		assertEqual(t, true, ok)
+		assertEqual(t, want, v)
+	}
+
+	v, ok := tr.KthLargest(1)
+	assertEqual(t, true, ok)
+	assertEqual(t, 8, v)
+	v, ok = tr.KthLargest(3)
+	assertEqual(t, true, ok)
+	assertEqual(t, 5, v)
+
+	for _, k := range []int{0, 6} {
+		_, ok = tr.Kth(k)
+		assertEqual(t, false, ok)
+		_, ok = tr.KthLargest(k)
+		assertEqual(t, false, ok)
+	}
+
+	_, ok = Fixed[int](3).Kth(1)
+	assertEqual(t, false, ok, "empty window has no values")
+}
+
+func Test_fixed_Range(t *testing.T) {
+	tr := makeFixed(8, 3, 5, 1, 5, 9, 2)
+	cases := []struct {
+		i, j int
+		want []int
+	}{
+		{i: 1, j: 7, want: []int{1, 2, 3, 5, 5, 8, 9}},
+		{i: 2, j: 4, want: []int{2, 3, 5}},
+		{i: 7, j: 5, want: []int{9, 8, 5}},
+		{i: 4, j: 4, want: []int{5}},
+		{i: -5, j: 2, want: []int{1, 2}},
+		{i: 100, j: 6, want: []int{9, 8}},
+		{i: 8, j: 10, want: nil},
+	}
+
+	for _, c := range cases {
+		got := slices.Collect(tr.Range(c.i, c.j))
+		assertEqual(t, true, slices.Equal(c.want, got), fmt.Sprintf("Range(%d, %d) = %v", c.i, c.j, got))
+	}
+
+	// Stops early
+	for v := range tr.Range(7, 1) {
+		assertEqual(t, 9, v)
+		break
+	}
+}
+
+func Test_fixed_positions(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithStats(Mean)}} {
+		tr := Fixed[int](3, opts...)
+		_, ok := tr.Newest()
+		assertEqual(t, false, ok)
+		_, ok = tr.Oldest()
+		assertEqual(t, false, ok)
+		assertEqual(t, 0, len(tr.Values(nil)))
+
+		tr.Put(1)
+		tr.Put(2)
+		v, _ := tr.Newest()
+		assertEqual(t, 2, v)
+		v, _ = tr.Oldest()
+		assertEqual(t, 1, v)
+		assertEqual(t, true, slices.Equal([]int{1, 2}, tr.Values(nil)))
+
+		tr.Put(3)
+		tr.Put(4) // replaces 1
+		tr.Put(5) // replaces 2
+		for age, want := range []int{5, 4, 3} {
+			v, ok = tr.At(age)
+			assertEqual(t, true, ok)
+			assertEqual(t, want, v)
+		}
+		_, ok = tr.At(3)
+		assertEqual(t, false, ok)
+		_, ok = tr.At(-1)
+		assertEqual(t, false, ok)
+
+		v, _ = tr.Oldest()
+		assertEqual(t, 3, v)
+		assertEqual(t, true, slices.Equal([]int{0, 3, 4, 5}, tr.Values([]int{0})))
+	}
+
+	t.Run("after PutAll", func(t *testing.T) {
+		tr := Fixed[int](3)
+		tr.PutAll([]int{9, 8, 7, 6})
+		assertEqual(t, true, slices.Equal([]int{8, 7, 6}, tr.Values(nil)))
+		tr.Put(5)
+		assertEqual(t, true, slices.Equal([]int{7, 6, 5}, tr.Values(nil)))
+	})
+}
+
+func Test_fixed_OnEvict(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithStats(Mean)}} {
+		var evicted []int
+		tr := Fixed[int](2, opts...)
+		tr.OnEvict(func(old int) {
+			assertEqual(t, 2, tr.Size(), "should call after the new value is added")
+			evicted = append(evicted, old)
+		})
+
+		tr.Put(1)
+		tr.Put(2)
+		assertEqual(t, 0, len(evicted))
+		tr.Put(3)
+		tr.PutAll([]int{4, 5, 6})
+		assertEqual(t, true, slices.Equal([]int{1, 2, 3, 4}, evicted))
+
+		tr.Reset()
+		tr.OnEvict(nil)
+		tr.PutAll([]int{7, 8, 9})
+		assertEqual(t, 4, len(evicted))
+	}
+}
+
+func Test_fixed_OnChange(t *testing.T) {
+	type change struct{ min, max int }
+	var changes []change
+	tr := Fixed[int](2)
+	tr.OnChange(func(min, max int) {
+		changes = append(changes, change{min, max})
+	})
+
+	tr.Put(0)   // first value
+	tr.Put(0)   // no change
+	tr.Put(5)   // replaces 0, max changes
+	tr.Put(3)   // replaces 0, min changes
+	tr.Put(5)   // replaces 5 with an equal value
+	tr.Put(100) // replaces 3, both change
+	assertEqual(t, true, slices.Equal([]change{{0, 0}, {0, 5}, {3, 5}, {5, 100}}, changes), fmt.Sprint(changes))
+}
+
+func Test_fixed_UndoLast(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		tr := Fixed[int](3)
+		assertEqual(t, false, tr.UndoLast())
+		assertEqual(t, false, tr.ReplaceNewest(1))
+	})
+
+	t.Run("single level", func(t *testing.T) {
+		tr := makeFixed(1, 2, 3)
+		tr.Put(4) // replaces 1
+		assertEqual(t, true, tr.UndoLast())
+		assertEqual(t, true, slices.Equal([]int{1, 2, 3}, tr.Values(nil)))
+		assertEqual(t, false, tr.UndoLast())
+
+		tr.Put(4)
+		tr.Put(5) // replaces 2
+		assertEqual(t, true, tr.UndoLast())
+		assertEqual(t, true, slices.Equal([]int{2, 3, 4}, tr.Values(nil)))
+	})
+
+	t.Run("rejected put", func(t *testing.T) {
+		tr := Fixed[int](4, WithOutlierRejection(1.5))
+		tr.PutAll([]int{10, 11, 12, 13})
+		tr.Put(12)
+		tr.Put(1000)
+		assertEqual(t, 1, tr.Rejected())
+		assertEqual(t, false, tr.UndoLast())
+	})
+
+	configs := []struct {
+		name string
+		opts []Option
+	}{
+		{"AllStats", nil},
+		{"MinMax", []Option{WithStats(MinMax)}},
+		{"Mean", []Option{WithStats(Mean)}},
+	}
+	for _, config := range configs {
+		t.Run("matches reference with "+config.name, func(t *testing.T) {
+			const size = 7
+			tr := Fixed[int](size, config.opts...)
+			c := tr.TrackQuantile(0.5)
+			var values []int
+			for i := 0; i < 2000; i++ {
+				v := rand.IntN(20)
+				switch op := rand.IntN(10); {
+				case op < 2 && tr.UndoLast():
+					values = values[:len(values)-1]
+				case op < 4 && tr.ReplaceNewest(v):
+					values[len(values)-1] = v
+				default:
+					tr.Put(v)
+					values = append(values, v)
+				}
+
+				expected := Fixed[int](size)
+				for _, v := range values[max(0, len(values)-size):] {
+					expected.Put(v)
+				}
+				if !assertEqual(t, true, slices.Equal(expected.Values(nil), tr.Values(nil)), "values should match") ||
+					!assertInDelta(t, expected.Mean(), tr.Mean(), 1e-9, "mean should match") {
+					t.Fatalf("put %d", i)
+				}
+
+				if tr.tracks(MinMax) {
+					assertEqual(t, expected.Min(), tr.Min(), "min should match")
+					assertEqual(t, expected.Max(), tr.Max(), "max should match")
+				}
+				if tr.tracks(Quantiles) {
+					assertRedBlackProperties(t, tr)
+					assertEqual(t, expected.Quantile(0.5), c.Value(), "cursor should match")
+				}
+			}
+		})
+	}
+}
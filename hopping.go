@@ -0,0 +1,66 @@
+package mwnd
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Hopping aggregates a stream of values into windows of a fixed length that advance
+// by a fixed hop. Each time the window advances, the Summary of the most recent
+// values is passed to a callback.
+//
+// Windows overlap when the hop is less than the length, and values are skipped when
+// the hop is greater than the length.
+type Hopping[T Numeric] struct {
+	w         *FixedWindow[T]
+	hop       int
+	n         int
+	quantiles []float64
+	emit      func(Summary[T])
+}
+
+// NewHopping initializes a Hopping window that emits the Summary of the last length
+// values after every hop values. Until length values have been Put, each Summary
+// covers all values so far.
+//
+// Each Summary includes the values of the requested quantiles. It returns an error
+// wrapping [ErrInvalidCapacity] if length is invalid, as for [NewFixed], [ErrInvalidHop]
+// if hop is not positive, [ErrNilEmit] if emit is nil, or [ErrInvalidQuantile] if any
+// quantile is outside of the range 0.0 to 1.0, inclusive.
+func NewHopping[T Numeric](length, hop int, emit func(Summary[T]), quantiles ...float64) (*Hopping[T], error) {
+	if hop < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidHop, hop)
+	}
+	if emit == nil {
+		return nil, ErrNilEmit
+	}
+	if err := validQuantiles(quantiles); err != nil {
+		return nil, err
+	}
+
+	w, err := NewFixed[T](length, WithStats(summaryStats(quantiles)))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Hopping[T]{
+		w:         w,
+		hop:       hop,
+		quantiles: slices.Clone(quantiles),
+		emit:      emit,
+	}, nil
+}
+
+// Put adds a new value to the window, emitting a Summary if the window has advanced
+// by a full hop.
+//
+// Time complexity of O(log n), where n is the length of the window.
+func (w *Hopping[T]) Put(v T) {
+	w.w.Put(v)
+	w.n++
+
+	if w.n == w.hop {
+		w.n = 0
+		w.emit(w.w.Summary(w.quantiles...))
+	}
+}
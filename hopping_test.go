@@ -0,0 +1,87 @@
+package mwnd
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func Test_hopping_Put(t *testing.T) {
+	t.Run("overlapping", func(t *testing.T) {
+		var got []Summary[int]
+		w, err := NewHopping[int](4, 2, func(s Summary[int]) { got = append(got, s) }, 1)
+		assertNil(t, err)
+		for v := range 8 {
+			w.Put(v)
+		}
+
+		if !assertEqual(t, 4, len(got)) {
+			return
+		}
+
+		wantMin := []int{0, 0, 2, 4}
+		wantMax := []int{1, 3, 5, 7}
+		for i, s := range got {
+			assertEqual(t, min(4, 2*(i+1)), s.Count)
+			assertEqual(t, wantMin[i], s.Min)
+			assertEqual(t, wantMax[i], s.Max)
+			p100, _ := s.Quantile(1)
+			assertEqual(t, wantMax[i], p100)
+		}
+	})
+
+	t.Run("skipping", func(t *testing.T) {
+		var got []Summary[int]
+		w, err := NewHopping[int](2, 3, func(s Summary[int]) { got = append(got, s) })
+		assertNil(t, err)
+		for v := range 6 {
+			w.Put(v)
+		}
+
+		if !assertEqual(t, 2, len(got)) {
+			return
+		}
+		assertEqual(t, 1, got[0].Min)
+		assertEqual(t, 2, got[0].Max)
+		assertEqual(t, 4, got[1].Min)
+		assertEqual(t, 5, got[1].Max)
+	})
+}
+
+func Test_NewHopping(t *testing.T) {
+	emit := func(Summary[int]) {}
+	cases := map[string]struct {
+		length, hop int
+		quantiles   []float64
+		want        error
+	}{
+		"zero length":      {0, 1, nil, ErrInvalidCapacity},
+		"zero hop":         {2, 0, nil, ErrInvalidHop},
+		"negative hop":     {2, -1, nil, ErrInvalidHop},
+		"invalid quantile": {2, 1, []float64{0.5, 1.5}, ErrInvalidQuantile},
+		"NaN quantile":     {2, 1, []float64{math.NaN()}, ErrInvalidQuantile},
+	}
+
+	for name, c := range cases {
+		w, err := NewHopping[int](c.length, c.hop, emit, c.quantiles...)
+		assertEqual(t, true, w == nil, name)
+		assertEqual(t, true, errors.Is(err, c.want), name)
+	}
+
+	w, err := NewHopping[int](2, 1, nil)
+	assertEqual(t, true, w == nil)
+	assertEqual(t, true, errors.Is(err, ErrNilEmit))
+}
+
+func Test_hopping_quantilesCopied(t *testing.T) {
+	var got Summary[int]
+	qs := []float64{0.5}
+	w, _ := NewHopping[int](3, 1, func(s Summary[int]) { got = s }, qs...)
+	qs[0] = 1
+	w.Put(1)
+	w.Put(2)
+	w.Put(3)
+
+	_, ok := got.Quantile(0.5)
+	assertEqual(t, true, ok, "should not follow changes to the caller's quantiles")
+}
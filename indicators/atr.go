@@ -0,0 +1,44 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/davidbacisin/go-mwnd"
+)
+
+// ATR computes the average true range, which is a measure of volatility.
+type ATR[T mwnd.Numeric] struct {
+	tr        *mwnd.ExponentialWindow[float64]
+	prevClose T
+	started   bool
+}
+
+// NewATR initializes an ATR with Wilder smoothing over the given period.
+// The conventional period is 14.
+func NewATR[T mwnd.Numeric](period int) *ATR[T] {
+	return &ATR[T]{
+		tr: mwnd.Exponential[float64](wilderAlpha(period)),
+	}
+}
+
+// Put adds the high, low, and closing prices of the next period.
+//
+// Time complexity of O(1).
+func (a *ATR[T]) Put(high, low, close T) {
+	tr := float64(high) - float64(low)
+	if a.started {
+		prev := float64(a.prevClose)
+		tr = max(tr, math.Abs(float64(high)-prev), math.Abs(float64(low)-prev))
+	}
+
+	a.prevClose = close
+	a.started = true
+	a.tr.Put(tr)
+}
+
+// Value returns the current average true range.
+//
+// Time complexity of O(1).
+func (a *ATR[T]) Value() float64 {
+	return a.tr.Mean()
+}
@@ -0,0 +1,10 @@
+// Package indicators provides technical indicators for financial time series, such as
+// RSI, MACD, the stochastic oscillator, and ATR, built on the moving windows of
+// [mwnd]. Every indicator updates in O(1) or O(log n) time per tick.
+//
+// Averages that are conventionally smoothed with Wilder's method, which uses a
+// weight of 1/n, are computed with an exponential window of weight
+// [mwnd.ExponentialAlphaForApproximatingFixed](2n-1), which is equivalent. Unlike
+// the textbook definitions, averages are seeded with the first value rather than a
+// simple average of the first n values, so early readings differ slightly.
+package indicators
@@ -0,0 +1,92 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func assertInDelta(t *testing.T, expected, actual, delta float64, msg string) {
+	t.Helper()
+	if math.Abs(expected-actual) > delta {
+		t.Errorf("%s\nwant: %v\ngot:  %v", msg, expected, actual)
+	}
+}
+
+func Test_wilderAlpha(t *testing.T) {
+	for _, n := range []int{1, 3, 14} {
+		assertInDelta(t, 1/float64(n), wilderAlpha(n), 1e-12, "should weight by 1/n")
+	}
+}
+
+func Test_RSI(t *testing.T) {
+	r := NewRSI[float64](14)
+	assertInDelta(t, 50, r.Value(), 0, "should be neutral without changes")
+
+	for i := range 20 {
+		r.Put(float64(i))
+	}
+	assertInDelta(t, 100, r.Value(), 0, "should be 100 with only gains")
+
+	for i := 20; i > 0; i-- {
+		r.Put(float64(i))
+	}
+	if v := r.Value(); v > 50 {
+		t.Errorf("should fall below 50 after sustained losses, got %v", v)
+	}
+
+	r = NewRSI[float64](2)
+	r.Put(10)
+	r.Put(12) // avg gain 2, avg loss 0
+	r.Put(11) // avg gain 1, avg loss 0.5
+	assertInDelta(t, 100*1/1.5, r.Value(), 1e-12, "unexpected RSI")
+}
+
+func Test_MACD(t *testing.T) {
+	m := NewMACD[int](12, 26, 9)
+	for range 10 {
+		m.Put(100)
+	}
+	assertInDelta(t, 0, m.MACD(), 0, "should converge for a constant price")
+	assertInDelta(t, 0, m.Signal(), 0, "should converge for a constant price")
+	assertInDelta(t, 0, m.Histogram(), 0, "should converge for a constant price")
+
+	for i := range 50 {
+		m.Put(100 + i)
+	}
+	if m.MACD() <= 0 {
+		t.Errorf("fast average should lead in an uptrend, got %v", m.MACD())
+	}
+	if m.Histogram() <= 0 {
+		t.Errorf("MACD should lead its signal in an accelerating uptrend, got %v", m.Histogram())
+	}
+	assertInDelta(t, m.MACD()-m.Signal(), m.Histogram(), 1e-12, "unexpected histogram")
+}
+
+func Test_Stochastic(t *testing.T) {
+	s := NewStochastic[int](3, 2)
+	s.Put(10, 10, 10)
+	assertInDelta(t, 50, s.K(), 0, "should be neutral without range")
+
+	s.Put(12, 8, 12)
+	assertInDelta(t, 100, s.K(), 0, "should close at the high")
+	assertInDelta(t, 75, s.D(), 0, "should average %K")
+
+	s.Put(11, 9, 9)
+	assertInDelta(t, 25, s.K(), 0, "unexpected %K")
+	assertInDelta(t, 62.5, s.D(), 0, "should average %K")
+
+	// Evicts the first and second periods, so the range becomes [9, 14]
+	s.Put(14, 10, 12)
+	s.Put(13, 10, 11)
+	assertInDelta(t, 40, s.K(), 0, "should look back over the period only")
+}
+
+func Test_ATR(t *testing.T) {
+	a := NewATR[float64](2)
+	a.Put(12, 8, 10)
+	assertInDelta(t, 4, a.Value(), 0, "should use the range of the first period")
+
+	// Gap up: the true range extends from the previous close
+	a.Put(16, 14, 15)
+	assertInDelta(t, 5, a.Value(), 1e-12, "should average true ranges 4 and 6")
+}
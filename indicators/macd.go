@@ -0,0 +1,51 @@
+package indicators
+
+import "github.com/davidbacisin/go-mwnd"
+
+// MACD computes the moving average convergence/divergence, which is the difference
+// between a fast and a slow exponential moving average of the price, along with a
+// signal line that is an exponential moving average of the MACD itself.
+type MACD[T mwnd.Numeric] struct {
+	fast, slow *mwnd.ExponentialWindow[T]
+	signal     *mwnd.ExponentialWindow[float64]
+}
+
+// NewMACD initializes a MACD with the given periods for the fast, slow, and signal
+// exponential moving averages. The conventional periods are 12, 26, and 9.
+func NewMACD[T mwnd.Numeric](fast, slow, signal int) *MACD[T] {
+	return &MACD[T]{
+		fast:   mwnd.Exponential[T](mwnd.ExponentialAlphaForApproximatingFixed(fast)),
+		slow:   mwnd.Exponential[T](mwnd.ExponentialAlphaForApproximatingFixed(slow)),
+		signal: mwnd.Exponential[float64](mwnd.ExponentialAlphaForApproximatingFixed(signal)),
+	}
+}
+
+// Put adds the closing price of the next period.
+//
+// Time complexity of O(1).
+func (m *MACD[T]) Put(close T) {
+	m.fast.Put(close)
+	m.slow.Put(close)
+	m.signal.Put(m.MACD())
+}
+
+// MACD returns the difference between the fast and slow moving averages.
+//
+// Time complexity of O(1).
+func (m *MACD[T]) MACD() float64 {
+	return m.fast.Mean() - m.slow.Mean()
+}
+
+// Signal returns the moving average of the MACD.
+//
+// Time complexity of O(1).
+func (m *MACD[T]) Signal() float64 {
+	return m.signal.Mean()
+}
+
+// Histogram returns the difference between the MACD and its signal line.
+//
+// Time complexity of O(1).
+func (m *MACD[T]) Histogram() float64 {
+	return m.MACD() - m.Signal()
+}
@@ -0,0 +1,55 @@
+package indicators
+
+import "github.com/davidbacisin/go-mwnd"
+
+// RSI computes the relative strength index, which measures the magnitude of
+// recent gains relative to recent losses on a scale of 0 to 100.
+type RSI[T mwnd.Numeric] struct {
+	gain, loss *mwnd.ExponentialWindow[float64]
+	prev       T
+	started    bool
+}
+
+// NewRSI initializes an RSI with Wilder smoothing over the given period.
+// The conventional period is 14.
+func NewRSI[T mwnd.Numeric](period int) *RSI[T] {
+	alpha := wilderAlpha(period)
+	return &RSI[T]{
+		gain: mwnd.Exponential[float64](alpha),
+		loss: mwnd.Exponential[float64](alpha),
+	}
+}
+
+// Put adds the closing price of the next period.
+//
+// Time complexity of O(1).
+func (r *RSI[T]) Put(close T) {
+	if !r.started {
+		r.prev = close
+		r.started = true
+		return
+	}
+
+	change := float64(close) - float64(r.prev)
+	r.prev = close
+	r.gain.Put(max(change, 0))
+	r.loss.Put(max(-change, 0))
+}
+
+// Value returns the current RSI between 0 and 100. If there have been no price
+// changes at all, then it returns 50.
+//
+// Time complexity of O(1).
+func (r *RSI[T]) Value() float64 {
+	gain, loss := r.gain.Mean(), r.loss.Mean()
+	if gain == 0 && loss == 0 {
+		return 50
+	}
+	return 100 * gain / (gain + loss)
+}
+
+// wilderAlpha returns the exponential weight for Wilder smoothing over n periods,
+// which is 1/n.
+func wilderAlpha(n int) float64 {
+	return mwnd.ExponentialAlphaForApproximatingFixed(2*n - 1)
+}
@@ -0,0 +1,53 @@
+package indicators
+
+import "github.com/davidbacisin/go-mwnd"
+
+// Stochastic computes the stochastic oscillator, which locates the closing price
+// within the range of prices over a lookback period on a scale of 0 to 100.
+type Stochastic[T mwnd.Numeric] struct {
+	high, low *mwnd.FixedWindow[T]
+	d         *mwnd.FixedWindow[float64]
+	k         float64
+}
+
+// NewStochastic initializes a Stochastic oscillator whose %K looks back over period
+// prices and whose %D is the simple moving average of %K over smoothing periods.
+// The conventional periods are 14 and 3.
+func NewStochastic[T mwnd.Numeric](period, smoothing int) *Stochastic[T] {
+	return &Stochastic[T]{
+		high: mwnd.Fixed[T](period, mwnd.WithStats(mwnd.MinMax)),
+		low:  mwnd.Fixed[T](period, mwnd.WithStats(mwnd.MinMax)),
+		d:    mwnd.Fixed[float64](smoothing, mwnd.WithStats(mwnd.Mean)),
+	}
+}
+
+// Put adds the high, low, and closing prices of the next period.
+//
+// Amortized time complexity of O(1).
+func (s *Stochastic[T]) Put(high, low, close T) {
+	s.high.Put(high)
+	s.low.Put(low)
+
+	hh, ll := float64(s.high.Max()), float64(s.low.Min())
+	if hh == ll {
+		s.k = 50
+	} else {
+		s.k = 100 * (float64(close) - ll) / (hh - ll)
+	}
+	s.d.Put(s.k)
+}
+
+// K returns the current %K between 0 and 100. If the highest high and lowest low
+// of the lookback period are equal, then it returns 50.
+//
+// Time complexity of O(1).
+func (s *Stochastic[T]) K() float64 {
+	return s.k
+}
+
+// D returns the current %D, which is the simple moving average of %K.
+//
+// Time complexity of O(1).
+func (s *Stochastic[T]) D() float64 {
+	return s.d.Mean()
+}
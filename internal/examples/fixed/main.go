@@ -25,12 +25,14 @@ func main() {
 	quantiles := examples.OpenOutputFile("quantiles.csv")
 	defer quantiles.Close()
 	fmt.Fprintln(quantiles, "x y p10 p25 p50 p75 p90 p99")
+	p10, p25, p50 := w.TrackQuantile(0.1), w.TrackQuantile(0.25), w.TrackQuantile(0.5)
+	p75, p90, p99 := w.TrackQuantile(0.75), w.TrackQuantile(0.9), w.TrackQuantile(0.99)
 
 	for i := 0.0; i < 300*math.Pi; i += 0.1 {
 		v := 5*math.Sin(i*0.01) + math.Sin(i*0.1)
 		w.Put(v)
 		examples.WriteLine(basic, i, v, w.Min(), w.Max(), w.Mean(), w.Variance())
-		examples.WriteLine(quantiles, i, v, w.Quantile(0.1), w.Quantile(0.25), w.Quantile(0.5), w.Quantile(0.75), w.Quantile(0.9), w.Quantile(0.99))
+		examples.WriteLine(quantiles, i, v, p10.Value(), p25.Value(), p50.Value(), p75.Value(), p90.Value(), p99.Value())
 	}
 
 	// Plot basic data
@@ -0,0 +1,187 @@
+package mwnd
+
+// MedianWindow tracks the median of a fixed number of values, like
+// FixedWindow.Quantile(0.5), with a smaller constant factor.
+//
+// The lower half of the values is kept in a max-heap and the upper half in a min-heap,
+// so that the median is always at the top of the lower heap. Each heap holds the
+// positions of its values in a ring buffer, and each position records where it is in
+// its heap, so that the oldest value can be deleted from the middle of a heap when it
+// is evicted. Put and Median avoid memory allocations.
+type MedianWindow[T Numeric] struct {
+	// values is a ring buffer of the values in the window
+	values []T
+
+	// at is the index of each position of the ring in its heap, and low reports
+	// whether that heap is the lower heap
+	at  []int32
+	low []bool
+
+	// lo is a max-heap of the lower half of values, which holds the extra value
+	// when the size is odd, and hi is a min-heap of the upper half
+	lo, hi []int32
+
+	// i is the position in the ring of the oldest value, which will be replaced by
+	// the next inserted value
+	i int
+}
+
+// NewMedian initializes a moving window with the fixed capacity for values. It returns
+// an error wrapping [ErrInvalidCapacity] if the capacity is invalid, as for [NewFixed].
+func NewMedian[T Numeric](capacity int) (*MedianWindow[T], error) {
+	if err := validCapacity(capacity); err != nil {
+		return nil, err
+	}
+
+	return &MedianWindow[T]{
+		values: make([]T, capacity),
+		at:     make([]int32, capacity),
+		low:    make([]bool, capacity),
+		lo:     make([]int32, 0, capacity),
+		hi:     make([]int32, 0, capacity),
+	}, nil
+}
+
+// Size returns the current number of values in the Window.
+func (w *MedianWindow[T]) Size() int {
+	return len(w.lo) + len(w.hi)
+}
+
+// Median returns the lower median of the values currently in the Window, which is
+// the same as FixedWindow.Quantile(0.5). If the Window has no values, then it
+// returns the zero value.
+//
+// Time complexity of O(1).
+func (w *MedianWindow[T]) Median() T {
+	if len(w.lo) == 0 {
+		var zero T
+		return zero
+	}
+	return w.values[w.lo[0]]
+}
+
+// Put adds a new value to the Window, first evicting the oldest value if the Window
+// is at capacity.
+//
+// Worst case time complexity of O(log n), where n is the capacity of the Window.
+func (w *MedianWindow[T]) Put(v T) {
+	k := int32(w.i)
+	if w.Size() == len(w.values) {
+		w.remove(k)
+	}
+
+	// The eviction may have emptied the lower heap, in which case v is compared to
+	// the upper heap instead
+	w.values[k] = v
+	switch {
+	case len(w.lo) > 0:
+		w.push(v <= w.values[w.lo[0]], k)
+	case len(w.hi) > 0:
+		w.push(v <= w.values[w.hi[0]], k)
+	default:
+		w.push(true, k)
+	}
+
+	// Restore the balance of the heaps, which never requires moving more than one value
+	if len(w.lo) > len(w.hi)+1 {
+		w.push(false, w.pop(true))
+	} else if len(w.hi) > len(w.lo) {
+		w.push(true, w.pop(false))
+	}
+
+	w.i = (w.i + 1) % len(w.values)
+}
+
+// heap returns the lower or upper heap.
+func (w *MedianWindow[T]) heap(low bool) *[]int32 {
+	if low {
+		return &w.lo
+	}
+	return &w.hi
+}
+
+// above reports whether the value at position a of the ring belongs above the value
+// at position b in the heap.
+func (w *MedianWindow[T]) above(low bool, a, b int32) bool {
+	if low {
+		return w.values[a] > w.values[b]
+	}
+	return w.values[a] < w.values[b]
+}
+
+// set places position k of the ring at index j of the heap.
+func (w *MedianWindow[T]) set(low bool, j int, k int32) {
+	(*w.heap(low))[j] = k
+	w.at[k] = int32(j)
+	w.low[k] = low
+}
+
+func (w *MedianWindow[T]) push(low bool, k int32) {
+	h := w.heap(low)
+	*h = append(*h, k)
+	w.set(low, len(*h)-1, k)
+	w.up(low, len(*h)-1)
+}
+
+// pop removes the top of the heap and returns its position in the ring.
+func (w *MedianWindow[T]) pop(low bool) int32 {
+	k := (*w.heap(low))[0]
+	w.remove(k)
+	return k
+}
+
+// remove deletes position k of the ring from whichever heap holds it.
+func (w *MedianWindow[T]) remove(k int32) {
+	low := w.low[k]
+	h := w.heap(low)
+	j := int(w.at[k])
+	last := len(*h) - 1
+	if j != last {
+		w.set(low, j, (*h)[last])
+	}
+	*h = (*h)[:last]
+
+	if j < last && !w.up(low, j) {
+		w.down(low, j)
+	}
+}
+
+// up moves the entry at index j of the heap toward the top until the heap is
+// ordered, reporting whether it moved.
+func (w *MedianWindow[T]) up(low bool, j int) bool {
+	h := *w.heap(low)
+	k := h[j]
+	start := j
+	for j > 0 {
+		parent := (j - 1) / 2
+		if !w.above(low, k, h[parent]) {
+			break
+		}
+		w.set(low, j, h[parent])
+		j = parent
+	}
+	w.set(low, j, k)
+	return j != start
+}
+
+// down moves the entry at index j of the heap toward the bottom until the heap is
+// ordered.
+func (w *MedianWindow[T]) down(low bool, j int) {
+	h := *w.heap(low)
+	k := h[j]
+	for {
+		child := 2*j + 1
+		if child >= len(h) {
+			break
+		}
+		if right := child + 1; right < len(h) && w.above(low, h[right], h[child]) {
+			child = right
+		}
+		if !w.above(low, h[child], k) {
+			break
+		}
+		w.set(low, j, h[child])
+		j = child
+	}
+	w.set(low, j, k)
+}
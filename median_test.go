@@ -0,0 +1,51 @@
+package mwnd
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func Test_median(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		w, _ := NewMedian[int](3)
+		assertEqual(t, 0, w.Size())
+		assertEqual(t, 0, w.Median())
+	})
+
+	t.Run("rolling four values", func(t *testing.T) {
+		w, _ := NewMedian[int](4)
+		w.Put(5)
+		assertEqual(t, 5, w.Median())
+		w.Put(1)
+		assertEqual(t, 1, w.Median(), "should be the lower median")
+		w.Put(3)
+		assertEqual(t, 3, w.Median())
+		w.Put(9)
+		assertEqual(t, 3, w.Median())
+
+		w.Put(2) // replaces 5
+		assertEqual(t, 4, w.Size())
+		assertEqual(t, 2, w.Median())
+		w.Put(8) // replaces 1
+		assertEqual(t, 3, w.Median())
+	})
+
+	t.Run("matches fixed", func(t *testing.T) {
+		checkRandomPuts(t, func(ref *FixedWindow[int]) (func(int), func(*testing.T) bool) {
+			w, _ := NewMedian[int](ref.capacity())
+			return w.Put, func(t *testing.T) bool {
+				return assertEqual(t, ref.Size(), w.Size(), "size should match") &&
+					assertEqual(t, ref.Quantile(0.5), w.Median(), "median should match")
+			}
+		})
+	})
+}
+
+func Test_NewMedian(t *testing.T) {
+	for _, capacity := range []int{-1, 0, math.MaxInt32} {
+		w, err := NewMedian[int](capacity)
+		assertEqual(t, true, w == nil)
+		assertEqual(t, true, errors.Is(err, ErrInvalidCapacity))
+	}
+}
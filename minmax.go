@@ -0,0 +1,76 @@
+package mwnd
+
+// MinMaxWindow aggregates a fixed number of values, like FixedWindow, but doesn't
+// support quantiles. In exchange, Put takes amortized constant time rather than
+// logarithmic time.
+//
+// Min and Max are tracked with monotonic deques: each deque holds only the values
+// that could still become the minimum or maximum as older values are evicted. Put and
+// all statistical operations avoid memory allocations.
+type MinMaxWindow[T Numeric] struct {
+	ring valueRing[T]
+	moments
+}
+
+// enforce compliance with interface
+var _ Window[float64] = (*MinMaxWindow[float64])(nil)
+
+// NewMinMax initializes a moving window with the fixed capacity for values. It returns
+// an error wrapping [ErrInvalidCapacity] if the capacity is invalid, as for [NewFixed].
+func NewMinMax[T Numeric](capacity int) (*MinMaxWindow[T], error) {
+	if err := validCapacity(capacity); err != nil {
+		return nil, err
+	}
+
+	return &MinMaxWindow[T]{
+		ring: newValueRing[T](capacity, true),
+	}, nil
+}
+
+// Size returns the current number of values in the Window.
+func (w *MinMaxWindow[T]) Size() int {
+	return w.n
+}
+
+// Min returns the lowest value currently in the Window.
+// If the Window has no values, then it returns the zero value.
+//
+// Time complexity of O(1).
+func (w *MinMaxWindow[T]) Min() T {
+	return w.ring.min()
+}
+
+// Max returns the highest value currently in the Window.
+// If the Window has no values, then it returns the zero value.
+//
+// Time complexity of O(1).
+func (w *MinMaxWindow[T]) Max() T {
+	return w.ring.max()
+}
+
+// Mean returns the arithmetic mean of all values currently in the Window.
+// If the Window has no values, then it returns 0.0.
+//
+// Time complexity of O(1).
+func (w *MinMaxWindow[T]) Mean() float64 {
+	return w.mean
+}
+
+// Variance returns the population variance of all values currently in the Window.
+// If the Window has no values, then it returns 0.0.
+//
+// Time complexity of O(1).
+func (w *MinMaxWindow[T]) Variance() float64 {
+	return w.variance()
+}
+
+// Put adds a new value to the Window. If the Window is at capacity, then the oldest value is
+// evicted to be replaced by the new value.
+//
+// Amortized time complexity of O(1).
+func (w *MinMaxWindow[T]) Put(v T) {
+	if old, evicted := w.ring.push(v); evicted {
+		w.remove(float64(old))
+	}
+	w.add(float64(v))
+}
@@ -0,0 +1,84 @@
+package mwnd
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func Test_minMax(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		w, _ := NewMinMax[int](3)
+		assertEqual(t, 0, w.Size())
+		assertEqual(t, 0, w.Min())
+		assertEqual(t, 0, w.Max())
+		assertEqual(t, 0.0, w.Mean())
+		assertEqual(t, 0.0, w.Variance())
+	})
+
+	t.Run("rolling three values", func(t *testing.T) {
+		w, _ := NewMinMax[int](3)
+		for _, v := range []int{1, 2, 3} {
+			w.Put(v)
+		}
+		assertEqual(t, 3, w.Size())
+		assertEqual(t, 1, w.Min())
+		assertEqual(t, 3, w.Max())
+		assertEqual(t, 2.0, w.Mean())
+		assertEqual(t, 2.0/3.0, w.Variance())
+
+		w.Put(4) // replaces 1
+		assertEqual(t, 3, w.Size())
+		assertEqual(t, 2, w.Min())
+		assertEqual(t, 4, w.Max())
+		assertEqual(t, 3.0, w.Mean())
+
+		w.Put(0) // replaces 2
+		w.Put(0) // replaces 3
+		assertEqual(t, 0, w.Min())
+		assertEqual(t, 4, w.Max())
+
+		w.Put(0) // replaces 4
+		assertEqual(t, 0, w.Min())
+		assertEqual(t, 0, w.Max(), "should keep the newest of equal values")
+		assertEqual(t, 0.0, w.Variance())
+	})
+
+	t.Run("rolling 50 values random", func(t *testing.T) {
+		const size = 50
+		values := make([]int, 0, size)
+		w, _ := NewMinMax[int](size)
+		for i := range 1000 {
+			v := rand.IntN(100)
+			if i >= size {
+				values[i%size] = v
+			} else {
+				values = append(values, v)
+			}
+
+			w.Put(v)
+
+			var sum float64
+			for _, v := range values {
+				sum += float64(v)
+			}
+
+			if !assertEqual(t, slices.Min(values), w.Min(), "min should match") ||
+				!assertEqual(t, slices.Max(values), w.Max(), "max should match") ||
+				!assertInDelta(t, sum/float64(len(values)), w.Mean(), 1e-9, "mean should match") {
+				t.Logf("failed at i=%d", i)
+				break
+			}
+		}
+	})
+}
+
+func Test_NewMinMax(t *testing.T) {
+	for _, capacity := range []int{-1, 0, math.MaxInt32} {
+		w, err := NewMinMax[int](capacity)
+		assertEqual(t, true, w == nil)
+		assertEqual(t, true, errors.Is(err, ErrInvalidCapacity))
+	}
+}
@@ -0,0 +1,60 @@
+package mwnd
+
+// moments accumulates the count, mean, and total sum of squared differences from
+// the mean (m2) of a set of values.
+type moments struct {
+	n        int
+	mean, m2 float64
+}
+
+// add includes v using Welford's algorithm for online variance, which is a
+// numerically stable approach.
+func (m *moments) add(v float64) {
+	m.n++
+	delta := v - m.mean
+	m.mean += delta / float64(m.n)
+	delta2 := v - m.mean
+	m.m2 += delta * delta2
+}
+
+// remove excludes v, which must have been added, by reversing Welford's algorithm.
+func (m *moments) remove(v float64) {
+	m.n--
+	if m.n == 0 {
+		m.mean = 0
+		m.m2 = 0
+		return
+	}
+
+	delta2 := v - m.mean
+	m.mean -= delta2 / float64(m.n)
+	delta := v - m.mean
+	m.m2 -= delta * delta2
+}
+
+// merge includes all values of o using the parallel algorithm of Chan et al.
+func (m *moments) merge(o moments) {
+	if o.n == 0 {
+		return
+	}
+
+	if m.n == 0 {
+		*m = o
+		return
+	}
+
+	n := m.n + o.n
+	delta := o.mean - m.mean
+	m.mean += delta * float64(o.n) / float64(n)
+	m.m2 += o.m2 + delta*delta*float64(m.n)*float64(o.n)/float64(n)
+	m.n = n
+}
+
+// variance returns the population variance. Removing values can accumulate
+// rounding error in m2, so the variance is clamped to be non-negative.
+func (m moments) variance() float64 {
+	if m.n == 0 {
+		return 0
+	}
+	return max(m.m2/float64(m.n), 0)
+}
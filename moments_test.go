@@ -0,0 +1,57 @@
+package mwnd
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func Test_moments_merge(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		var a, b moments
+		a.merge(b)
+		assertEqual(t, moments{}, a)
+
+		b.add(2)
+		a.merge(b)
+		assertEqual(t, b, a)
+
+		a.merge(moments{})
+		assertEqual(t, b, a)
+	})
+
+	t.Run("random", func(t *testing.T) {
+		var all, a, b moments
+		for i := range 1000 {
+			v := rand.Float64() * 1000
+			all.add(v)
+			if i%3 == 0 {
+				a.add(v)
+			} else {
+				b.add(v)
+			}
+		}
+
+		a.merge(b)
+		assertEqual(t, all.n, a.n)
+		assertInDelta(t, all.mean, a.mean, 1e-9)
+		assertInDelta(t, all.variance(), a.variance(), 1e-6)
+	})
+}
+
+func Test_moments_remove(t *testing.T) {
+	var m moments
+	for _, v := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		m.add(v)
+	}
+
+	m.remove(2)
+	m.remove(9)
+	assertEqual(t, 6, m.n)
+	assertInDelta(t, 29.0/6.0, m.mean, 1e-12)
+	assertInDelta(t, 41.0/36.0, m.variance(), 1e-12)
+
+	for _, v := range []float64{4, 4, 4, 5, 5, 7} {
+		m.remove(v)
+	}
+	assertEqual(t, moments{}, m, "should reset exactly once empty")
+}
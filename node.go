@@ -18,105 +18,162 @@ type Numeric interface {
 		~float32 | ~float64
 }
 
+// nilNode is the index of the sentinel node at the start of every tree, which stands
+// in for the absence of a node. The sentinel is always black, has no children, and
+// must never be modified.
+const nilNode int32 = 0
+
+// node links to its parent and children by their indices in the tree rather than by
+// pointers, so that a tree contains no pointers at all. This halves the size of each
+// node on 64-bit platforms and lets the garbage collector skip the tree entirely.
 type node[T Numeric] struct {
 	value               T
-	parent, left, right *node[T]
-	color               color
+	parent, left, right int32
 
 	// nLeft and nRight are the number of child nodes in each direction
-	nLeft, nRight int
+	nLeft, nRight int32
+	color         color
 }
 
-func (n *node[T]) setLeft(l *node[T]) {
-	if n == nil {
+// tree is a slice of nodes, addressed by index, in which index 0 is the sentinel nilNode.
+type tree[T Numeric] []node[T]
+
+func (ns tree[T]) setLeft(n, l int32) {
+	if n == nilNode {
 		return
 	}
 
-	n.left = l
+	ns[n].left = l
 
-	if l != nil {
-		l.parent = n
+	if l != nilNode {
+		ns[l].parent = n
 	}
 }
 
-func (n *node[T]) setRight(r *node[T]) {
-	if n == nil {
+func (ns tree[T]) setRight(n, r int32) {
+	if n == nilNode {
 		return
 	}
 
-	n.right = r
+	ns[n].right = r
 
-	if r != nil {
-		r.parent = n
+	if r != nilNode {
+		ns[r].parent = n
 	}
 }
 
-func (n *node[T]) safeColor() color {
-	if n == nil {
+func (ns tree[T]) safeColor(n int32) color {
+	if n == nilNode {
 		return black
 	}
-	return n.color
+	return ns[n].color
 }
 
-func (n *node[T]) grandparent() *node[T] {
-	if n == nil || n.parent == nil {
-		return nil
+func (ns tree[T]) grandparent(n int32) int32 {
+	if n == nilNode || ns[n].parent == nilNode {
+		return nilNode
 	}
-	return n.parent.parent
+	return ns[ns[n].parent].parent
 }
 
-func (n *node[T]) sibling() *node[T] {
-	if n == nil || n.parent == nil {
-		return nil
+func (ns tree[T]) sibling(n int32) int32 {
+	if n == nilNode || ns[n].parent == nilNode {
+		return nilNode
 	}
-	if n == n.parent.left {
-		return n.parent.right
+
+	p := ns[n].parent
+	if n == ns[p].left {
+		return ns[p].right
 	}
-	return n.parent.left
+	return ns[p].left
 }
 
-func (n *node[T]) grandparentAndUncle() (*node[T], *node[T]) {
-	g := n.grandparent()
-	if g == nil {
-		return nil, nil
+func (ns tree[T]) grandparentAndUncle(n int32) (int32, int32) {
+	g := ns.grandparent(n)
+	if g == nilNode {
+		return nilNode, nilNode
 	}
-	return g, n.parent.sibling()
+	return g, ns.sibling(ns[n].parent)
 }
 
-func (n *node[T]) subtreeSize() int {
-	if n == nil {
+func (ns tree[T]) subtreeSize(n int32) int32 {
+	if n == nilNode {
 		return 0
 	}
-	return n.nLeft + n.nRight + 1
+	return ns[n].nLeft + ns[n].nRight + 1
+}
+
+// rank returns the 1-indexed position of n in the in-order traversal of its tree.
+func (ns tree[T]) rank(n int32) int32 {
+	r := ns[n].nLeft + 1
+	for p := ns[n].parent; p != nilNode; n, p = p, ns[p].parent {
+		if ns[p].right == n {
+			r += ns[p].nLeft + 1
+		}
+	}
+	return r
+}
+
+// next returns the in-order successor of n, or nilNode if n is the last node.
+func (ns tree[T]) next(n int32) int32 {
+	if r := ns[n].right; r != nilNode {
+		for ns[r].left != nilNode {
+			r = ns[r].left
+		}
+		return r
+	}
+
+	p := ns[n].parent
+	for p != nilNode && ns[p].right == n {
+		n, p = p, ns[p].parent
+	}
+	return p
+}
+
+// prev returns the in-order predecessor of n, or nilNode if n is the first node.
+func (ns tree[T]) prev(n int32) int32 {
+	if l := ns[n].left; l != nilNode {
+		for ns[l].right != nilNode {
+			l = ns[l].right
+		}
+		return l
+	}
+
+	p := ns[n].parent
+	for p != nilNode && ns[p].left == n {
+		n, p = p, ns[p].parent
+	}
+	return p
 }
 
-func (n *node[T]) String() string {
+// sprint formats the subtree rooted at n for debugging.
+func (ns tree[T]) sprint(n int32) string {
 	var sb strings.Builder
-	printHelper(n, 0, &sb)
+	ns.printHelper(n, 0, &sb)
 	return sb.String()
 }
 
-func printHelper[T Numeric](n *node[T], level int, sb *strings.Builder) {
-	if n == nil {
+func (ns tree[T]) printHelper(n int32, level int, sb *strings.Builder) {
+	if n == nilNode {
 		sb.WriteString("<empty>")
 		return
 	}
 
-	if n.left != nil {
-		printHelper(n.left, level+1, sb)
+	if ns[n].left != nilNode {
+		ns.printHelper(ns[n].left, level+1, sb)
 	}
 
 	for i := 0; i < level; i++ {
 		sb.WriteString("   ")
 	}
 
-	if n.color == black {
-		sb.WriteString(fmt.Sprintf(" %v \n", n.value))
+	if ns[n].color == black {
+		sb.WriteString(fmt.Sprintf(" %v \n", ns[n].value))
 	} else {
-		sb.WriteString(fmt.Sprintf("<%v>\n", n.value))
+		sb.WriteString(fmt.Sprintf("<%v>\n", ns[n].value))
 	}
 
-	if n.right != nil {
-		printHelper(n.right, level+1, sb)
+	if ns[n].right != nilNode {
+		ns.printHelper(ns[n].right, level+1, sb)
 	}
 }
@@ -5,85 +5,90 @@ import "testing"
 func Test_node_safeColor(t *testing.T) {
 	cases := []struct {
 		name     string
-		node     *node[int]
+		node     int32
 		expected color
 	}{
 		{
 			name:     "nil node",
-			node:     nil,
+			node:     nilNode,
 			expected: black,
 		},
 		{
 			name:     "black node",
-			node:     &node[int]{color: black},
+			node:     1,
 			expected: black,
 		},
 		{
 			name:     "red node",
-			node:     &node[int]{color: red},
+			node:     2,
 			expected: red,
 		},
 	}
 
+	ns := tree[int]{{}, {color: black}, {color: red}}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			t.Parallel()
-			actual := c.node.safeColor()
+			actual := ns.safeColor(c.node)
 			assertEqual(t, c.expected, actual)
 		})
 	}
 }
 
-func Test_node_relationships(t *testing.T) {
-	root := &node[int]{value: 0}
-	left := &node[int]{value: 1}
-	leftLeft := &node[int]{value: 2}
-	leftRight := &node[int]{value: 3}
-	right := &node[int]{value: 4}
-	rightLeft := &node[int]{value: 5}
-	rightRight := &node[int]{value: 6}
+// makeTestTree links seven nodes with values 0 through 6 into a tree of depth three.
+func makeTestTree() (ns tree[int], root, left, leftLeft, leftRight, right, rightLeft, rightRight int32) {
+	ns = make(tree[int], 8)
+	root, left, leftLeft, leftRight, right, rightLeft, rightRight = 1, 2, 3, 4, 5, 6, 7
+	for i := range ns[1:] {
+		ns[i+1].value = i
+	}
 
 	// Link the nodes
-	root.setLeft(left)
-	left.setLeft(leftLeft)
-	left.setRight(leftRight)
-	root.setRight(right)
-	right.setLeft(rightLeft)
-	right.setRight(rightRight)
+	ns.setLeft(root, left)
+	ns.setLeft(left, leftLeft)
+	ns.setRight(left, leftRight)
+	ns.setRight(root, right)
+	ns.setLeft(right, rightLeft)
+	ns.setRight(right, rightRight)
+	return
+}
+
+func Test_node_relationships(t *testing.T) {
+	ns, root, left, leftLeft, leftRight, right, rightLeft, rightRight := makeTestTree()
 
 	cases := []struct {
 		name        string
-		n           *node[int]
-		grandparent *node[int]
-		uncle       *node[int]
-		sibling     *node[int]
+		n           int32
+		grandparent int32
+		uncle       int32
+		sibling     int32
 	}{
 		{
 			name:        "nil",
-			n:           nil,
-			grandparent: nil,
-			uncle:       nil,
-			sibling:     nil,
+			n:           nilNode,
+			grandparent: nilNode,
+			uncle:       nilNode,
+			sibling:     nilNode,
 		},
 		{
 			name:        "root",
 			n:           root,
-			grandparent: nil,
-			uncle:       nil,
-			sibling:     nil,
+			grandparent: nilNode,
+			uncle:       nilNode,
+			sibling:     nilNode,
 		},
 		{
 			name:        "left",
 			n:           left,
-			grandparent: nil,
-			uncle:       nil,
+			grandparent: nilNode,
+			uncle:       nilNode,
 			sibling:     right,
 		},
 		{
 			name:        "right",
 			n:           right,
-			grandparent: nil,
-			uncle:       nil,
+			grandparent: nilNode,
+			uncle:       nilNode,
 			sibling:     left,
 		},
 		{
@@ -119,8 +124,8 @@ func Test_node_relationships(t *testing.T) {
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			t.Parallel()
-			grandparent, uncle := c.n.grandparentAndUncle()
-			sibling := c.n.sibling()
+			grandparent, uncle := ns.grandparentAndUncle(c.n)
+			sibling := ns.sibling(c.n)
 			assertEqual(t, c.grandparent, grandparent)
 			assertEqual(t, c.uncle, uncle)
 			assertEqual(t, c.sibling, sibling)
@@ -129,21 +134,7 @@ func Test_node_relationships(t *testing.T) {
 }
 
 func Test_node_String(t *testing.T) {
-	root := &node[int]{value: 0}
-	left := &node[int]{value: 1}
-	leftLeft := &node[int]{value: 2}
-	leftRight := &node[int]{value: 3}
-	right := &node[int]{value: 4}
-	rightLeft := &node[int]{value: 5}
-	rightRight := &node[int]{value: 6}
-
-	// Link the nodes
-	root.setLeft(left)
-	left.setLeft(leftLeft)
-	left.setRight(leftRight)
-	root.setRight(right)
-	right.setLeft(rightLeft)
-	right.setRight(rightRight)
+	ns, root, _, _, _, _, _, _ := makeTestTree()
 
-	assertEqual(t, "       2 \n    1 \n       3 \n 0 \n       5 \n    4 \n       6 \n", root.String())
+	assertEqual(t, "       2 \n    1 \n       3 \n 0 \n       5 \n    4 \n       6 \n", ns.sprint(root))
 }
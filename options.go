@@ -0,0 +1,103 @@
+package mwnd
+
+// Option configures optional behavior of a moving window at construction.
+type Option func(*options)
+
+type options struct {
+	stats Stat
+
+	// outlierK is the Tukey fence multiplier used to reject outliers. Outlier
+	// rejection is disabled when rejectOutliers is false.
+	outlierK       float64
+	rejectOutliers bool
+
+	// readmitAfter is the number of consecutive rejected outliers after which outliers
+	// are accepted again, or zero to always reject them
+	readmitAfter int
+
+	// sketchAccuracy is the relative accuracy of the quantile sketch of each bucket of
+	// a BucketedWindow, which only has sketches if sketch is true
+	sketchAccuracy float64
+	sketch         bool
+}
+
+func newOptions(opts []Option) options {
+	o := options{stats: AllStats}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Stat identifies statistics that a window computes. Stats may be combined with
+// bitwise OR, such as Mean|Variance.
+type Stat uint8
+
+// The statistics that may be selected with [WithStats].
+const (
+	Mean Stat = 1 << iota
+	Variance
+	MinMax
+	Quantiles
+
+	AllStats = Mean | Variance | MinMax | Quantiles
+)
+
+// WithStats configures a FixedWindow to compute only the selected statistics, so that
+// it can use the cheapest structure that supports them. Methods for statistics that
+// aren't selected return the zero value. Mean and Variance are always available
+// since they cost almost nothing to compute.
+//
+// By default, a FixedWindow computes AllStats.
+func WithStats(stats Stat) Option {
+	return func(o *options) {
+		o.stats = stats
+	}
+}
+
+// WithOutlierRejection configures a FixedWindow to discard any value passed to Put
+// that lies outside the Tukey fences of the values currently in the window, where k
+// is the multiplier of the interquartile range. Common choices of k are 1.5 for
+// outliers and 3.0 for far outliers. See [FixedWindow.IsOutlier].
+//
+// Values are only rejected once the window has reached its capacity, so that a
+// partially filled window doesn't reject values based on too few observations.
+// Outliers are rejected however many arrive in a row, so a window whose input shifts
+// to a new level keeps the old level. Add [WithOutlierReadmission] to follow such
+// shifts instead.
+// Outlier rejection implies that the window tracks [Quantiles].
+func WithOutlierRejection(k float64) Option {
+	return func(o *options) {
+		o.outlierK = k
+		o.rejectOutliers = true
+	}
+}
+
+// WithOutlierReadmission configures a FixedWindow created with [WithOutlierRejection]
+// to accept outliers again after n of them have been rejected in a row. Such a run
+// suggests that the input has shifted to a new level rather than produced garbage, so
+// outliers are accepted until the window follows the new level and the next value is
+// not an outlier. A run of n garbage values is then rejected, but longer runs enter
+// the window, so choose n longer than any expected burst of garbage. See
+// [FixedWindow.Put].
+//
+// By default, or if n is not positive, outliers are always rejected.
+func WithOutlierReadmission(n int) Option {
+	return func(o *options) {
+		o.readmitAfter = max(n, 0)
+	}
+}
+
+// WithSketch configures a BucketedWindow to estimate quantiles by keeping a DDSketch
+// in each bucket, as in [SketchWindow], with the given relative accuracy, such as 0.01
+// for 1%. Since sketches merge exactly, the estimate covers the same values as the
+// other statistics of the window.
+//
+// Each sketch holds up to 2 stores of 2048 bins, though values within a few orders of
+// magnitude of each other need only a few hundred bins at 1% relative accuracy.
+func WithSketch(relativeAccuracy float64) Option {
+	return func(o *options) {
+		o.sketchAccuracy = relativeAccuracy
+		o.sketch = true
+	}
+}
@@ -0,0 +1,64 @@
+package mwnd
+
+import "sync/atomic"
+
+// Publisher shares the statistics of a window between a single writer and any number of
+// concurrent readers without locks. The writer calls Put, and every n Puts the Publisher
+// stores a new Summary of the window in an atomic pointer. Readers call Load to get the
+// most recently published Summary, so they never block the writer or each other.
+//
+// Put and Publish must only be called by one goroutine at a time, while Load may be
+// called from any goroutine.
+type Publisher[T Numeric] struct {
+	w         Window[T]
+	every     int
+	pending   int
+	quantiles []float64
+	summary   atomic.Pointer[Summary[T]]
+}
+
+// NewPublisher initializes a Publisher that publishes the Summary of w, including the
+// values of the requested quantiles, after every n Puts. An initial Summary is
+// published immediately, so Load never returns nil.
+//
+// If w has a method Summary(qs ...float64), such as FixedWindow, then it produces each
+// Summary. Otherwise, the Summary is assembled from the methods of Window, and the
+// quantiles are reported as Missing.
+func NewPublisher[T Numeric](w Window[T], n int, quantiles ...float64) *Publisher[T] {
+	p := &Publisher[T]{
+		w:         w,
+		every:     max(n, 1),
+		quantiles: quantiles,
+	}
+	p.Publish()
+	return p
+}
+
+// Put adds a new value to the window, publishing a new Summary if n values have been
+// Put since the last one was published.
+//
+// Time complexity of O(1) plus the time complexity of Put and, when publishing,
+// Summary of the window.
+func (p *Publisher[T]) Put(v T) {
+	p.w.Put(v)
+	p.pending++
+	if p.pending >= p.every {
+		p.Publish()
+	}
+}
+
+// Publish immediately publishes a new Summary of the window, such as before a pause in
+// the stream of values.
+func (p *Publisher[T]) Publish() {
+	s := summarize(p.w, p.quantiles)
+	p.summary.Store(&s)
+	p.pending = 0
+}
+
+// Load returns the most recently published Summary. The Summary is shared by all readers
+// and must not be modified.
+//
+// Time complexity of O(1).
+func (p *Publisher[T]) Load() *Summary[T] {
+	return p.summary.Load()
+}
@@ -0,0 +1,74 @@
+package mwnd
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_publisher(t *testing.T) {
+	t.Run("publishes every n", func(t *testing.T) {
+		p := NewPublisher[int](Fixed[int](10), 3, 0.5)
+		assertEqual(t, 0, p.Load().Count, "should publish an initial summary")
+
+		p.Put(1)
+		p.Put(2)
+		assertEqual(t, 0, p.Load().Count)
+		p.Put(3)
+		s := p.Load()
+		assertEqual(t, 3, s.Count)
+		assertEqual(t, 2.0, s.Mean)
+		v, ok := s.Quantile(0.5)
+		assertEqual(t, true, ok)
+		assertEqual(t, 2, v)
+
+		p.Put(4)
+		p.Publish()
+		assertEqual(t, 4, p.Load().Count)
+		assertEqual(t, 3, s.Count, "published summaries should not change")
+	})
+
+	t.Run("summary without quantiles", func(t *testing.T) {
+		p := NewPublisher[float64](Exponential[float64](0.5), 1, 0.5)
+		p.Put(2)
+		assertEqual(t, 2.0, p.Load().Mean)
+		assertEqual(t, 0, len(p.Load().Quantiles))
+	})
+
+	t.Run("window without summary", func(t *testing.T) {
+		w, _ := NewMinMax[int](2)
+		p := NewPublisher[int](w, 1)
+		p.Put(2)
+		p.Put(4)
+		s := p.Load()
+		assertEqual(t, 2, s.Count)
+		assertEqual(t, 2, s.Min)
+		assertEqual(t, 4, s.Max)
+	})
+
+	t.Run("concurrent readers", func(t *testing.T) {
+		p := NewPublisher[int](Fixed[int](100), 10, 0.5, 0.99)
+		var wg sync.WaitGroup
+		done := make(chan struct{})
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-done:
+						return
+					default:
+					}
+					s := p.Load()
+					assertLessOrEqual(t, s.Min, s.Max)
+				}
+			}()
+		}
+
+		for i := range 10000 {
+			p.Put(i % 1000)
+		}
+		close(done)
+		wg.Wait()
+	})
+}
@@ -0,0 +1,142 @@
+package mwnd
+
+import "slices"
+
+// valueRing is a ring buffer of the most recent values of a stream, which optionally
+// tracks their minimum and maximum with monotonic deques. It backs MinMaxWindow, and
+// FixedWindow when the latter doesn't track quantiles. The owner of a valueRing keeps
+// the moments of its values.
+type valueRing[T Numeric] struct {
+	values []T
+
+	// seq is the total number of values ever pushed, which is also the position in the
+	// stream of the next value
+	seq uint64
+
+	// lo and hi track the min and max if minMax is true
+	lo, hi monotonicDeque[T]
+	minMax bool
+}
+
+func newValueRing[T Numeric](capacity int, minMax bool) valueRing[T] {
+	r := valueRing[T]{
+		values: make([]T, capacity),
+		minMax: minMax,
+	}
+	if minMax {
+		r.lo = newMonotonicDeque[T](capacity, false)
+		r.hi = newMonotonicDeque[T](capacity, true)
+	}
+	return r
+}
+
+// size returns the number of values in the ring.
+func (r *valueRing[T]) size() int {
+	return int(min(r.seq, uint64(len(r.values))))
+}
+
+// slot returns the index in values of the value at position seq of the stream.
+func (r *valueRing[T]) slot(seq uint64) int {
+	return int(seq % uint64(len(r.values)))
+}
+
+// push adds v to the ring, returning the oldest value if the ring was full and v
+// replaced it.
+//
+// Amortized time complexity of O(1).
+func (r *valueRing[T]) push(v T) (old T, evicted bool) {
+	capacity := uint64(len(r.values))
+	i := r.slot(r.seq)
+	if r.seq >= capacity {
+		if r.minMax {
+			r.lo.evict(r.seq - capacity)
+			r.hi.evict(r.seq - capacity)
+		}
+		old, evicted = r.values[i], true
+	}
+
+	r.values[i] = v
+	if r.minMax {
+		r.lo.push(r.seq, v)
+		r.hi.push(r.seq, v)
+	}
+	r.seq++
+	return old, evicted
+}
+
+// unpush removes the newest value from the ring and returns it, restoring old as the
+// oldest value if the push of the newest value evicted it.
+//
+// Time complexity of O(n), where n is the capacity of the ring, if it tracks the min
+// and max, or O(1) otherwise.
+func (r *valueRing[T]) unpush(old T, evicted bool) T {
+	r.seq--
+	i := r.slot(r.seq)
+	v := r.values[i]
+	if evicted {
+		r.values[i] = old
+	}
+	r.rebuildDeques()
+	return v
+}
+
+// replaceNewest replaces the newest value in the ring with v and returns the value
+// that it replaced. The ring must not be empty.
+//
+// Time complexity of O(n), where n is the capacity of the ring, if it tracks the min
+// and max, or O(1) otherwise.
+func (r *valueRing[T]) replaceNewest(v T) T {
+	i := r.slot(r.seq - 1)
+	old := r.values[i]
+	r.values[i] = v
+	r.rebuildDeques()
+	return old
+}
+
+// at returns the value that was pushed age values before the newest value, where age
+// must be less than the size of the ring.
+func (r *valueRing[T]) at(age int) T {
+	return r.values[r.slot(r.seq-1-uint64(age))]
+}
+
+// min returns the lowest value in the ring, or the zero value if it is empty or
+// doesn't track the min and max.
+func (r *valueRing[T]) min() T {
+	return r.lo.front()
+}
+
+// max returns the highest value in the ring, or the zero value if it is empty or
+// doesn't track the min and max.
+func (r *valueRing[T]) max() T {
+	return r.hi.front()
+}
+
+// rebuildDeques refills the monotonic deques from the values, if the ring tracks them.
+func (r *valueRing[T]) rebuildDeques() {
+	if !r.minMax {
+		return
+	}
+
+	r.lo.reset()
+	r.hi.reset()
+	for age := r.size() - 1; age >= 0; age-- {
+		seq := r.seq - 1 - uint64(age)
+		r.lo.push(seq, r.at(age))
+		r.hi.push(seq, r.at(age))
+	}
+}
+
+func (r *valueRing[T]) reset() {
+	r.seq = 0
+	r.lo.reset()
+	r.hi.reset()
+}
+
+// clone returns a copy of the ring that shares no memory with it.
+func (r *valueRing[T]) clone() valueRing[T] {
+	c := *r
+	c.values = slices.Clone(r.values)
+	c.lo = r.lo.clone()
+	c.hi = r.hi.clone()
+	return c
+}
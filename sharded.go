@@ -0,0 +1,132 @@
+package mwnd
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+)
+
+// ShardedExponentialWindow approximates an ExponentialWindow that many goroutines Put
+// values into concurrently. Each Put goes to one of several shards, chosen at random,
+// each with its own ExponentialWindow and lock, so that concurrent Puts rarely contend.
+// Reads merge the Summaries of all shards with [Summary.Merge].
+//
+// Each shard receives about 1/s of the values, where s is the number of shards, so its
+// alpha is increased to 1-(1-alpha)^s to decay at the same rate per value Put to the
+// whole window. The merged statistics are still approximate, because the shards are out
+// of phase: the weight of a value depends on how many values its own shard has received
+// since, which varies randomly around the number received by the whole window, and the
+// newest values of each shard are weighted most heavily regardless of which shard has
+// the newest value overall. After a step change in the input, the merged mean deviates
+// from that of a single ExponentialWindow by up to about alpha*s/4 times the size of the
+// step, so keep alpha*s well below 1 for a close match. Min and Max are exact, while
+// Variance has the same meaning as that of ExponentialWindow.
+//
+// All methods are safe to call concurrently.
+type ShardedExponentialWindow[T Numeric] struct {
+	shards []expShard[T]
+}
+
+type expShard[T Numeric] struct {
+	mu sync.Mutex
+	w  ExponentialWindow[T]
+
+	// pad separates shards onto different cache lines to avoid false sharing
+	_ [64]byte
+}
+
+// enforce compliance with interface
+var _ Window[float64] = (*ShardedExponentialWindow[float64])(nil)
+
+// NewShardedExponential initializes a sharded moving window with the provided weight
+// alpha and number of shards, such as [runtime.GOMAXPROCS](0) for one shard per
+// processor. It returns an error wrapping [ErrInvalidAlpha] if alpha is invalid, as for
+// [NewExponential], or [ErrInvalidShards] if shards is not positive.
+func NewShardedExponential[T Numeric](alpha float64, shards int) (*ShardedExponentialWindow[T], error) {
+	if err := validAlpha(alpha); err != nil {
+		return nil, err
+	}
+	if shards < 1 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidShards, shards)
+	}
+
+	w := &ShardedExponentialWindow[T]{
+		shards: make([]expShard[T], shards),
+	}
+	shardAlpha := 1 - math.Pow(1-alpha, float64(shards))
+	for i := range w.shards {
+		w.shards[i].w.alpha = shardAlpha
+	}
+	return w, nil
+}
+
+// Put adds a new value to a randomly chosen shard of the Window.
+//
+// Time complexity of O(1).
+func (w *ShardedExponentialWindow[T]) Put(v T) {
+	s := &w.shards[rand.IntN(len(w.shards))]
+	s.mu.Lock()
+	s.w.Put(v)
+	s.mu.Unlock()
+}
+
+// Summary returns the merged statistics of all shards. Calling it once is cheaper than
+// calling each of the other methods, which each merge all shards. Like
+// [ExponentialWindow.Summary], it has no Quantiles.
+//
+// Time complexity of O(s), where s is the number of shards.
+func (w *ShardedExponentialWindow[T]) Summary(qs ...float64) Summary[T] {
+	var merged Summary[T]
+	for i := range w.shards {
+		s := &w.shards[i]
+		s.mu.Lock()
+		summary := s.w.Summary()
+		s.mu.Unlock()
+		merged = merged.Merge(summary)
+	}
+
+	if len(qs) > 0 {
+		merged.Missing |= Quantiles
+	}
+	return merged
+}
+
+// Size returns the number of values added to the Window.
+//
+// Time complexity of O(s), where s is the number of shards.
+func (w *ShardedExponentialWindow[T]) Size() int {
+	return w.Summary().Count
+}
+
+// Min returns the lowest value ever observed by the Window.
+// If the Window has no values, then it returns the zero value.
+//
+// Time complexity of O(s), where s is the number of shards.
+func (w *ShardedExponentialWindow[T]) Min() T {
+	return w.Summary().Min
+}
+
+// Max returns the highest value ever observed by the Window.
+// If the Window has no values, then it returns the zero value.
+//
+// Time complexity of O(s), where s is the number of shards.
+func (w *ShardedExponentialWindow[T]) Max() T {
+	return w.Summary().Max
+}
+
+// Mean returns the approximate exponentially-weighted moving average of all values ever
+// added to the Window. If the Window has no values, then it returns 0.0.
+//
+// Time complexity of O(s), where s is the number of shards.
+func (w *ShardedExponentialWindow[T]) Mean() float64 {
+	return w.Summary().Mean
+}
+
+// Variance returns the approximate exponentially-weighted moving variance of all values
+// ever added to the Window. If the Window has no values, then it returns 0.0.
+//
+// Time complexity of O(s), where s is the number of shards.
+func (w *ShardedExponentialWindow[T]) Variance() float64 {
+	return w.Summary().Variance
+}
@@ -0,0 +1,83 @@
+package mwnd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+)
+
+func Test_shardedExponential(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		w, _ := NewShardedExponential[int](0.1, 4)
+		assertEqual(t, 0, w.Size())
+		assertEqual(t, 0, w.Min())
+		assertEqual(t, 0, w.Max())
+		assertEqual(t, 0.0, w.Mean())
+		assertEqual(t, 0.0, w.Variance())
+	})
+
+	t.Run("shard alpha", func(t *testing.T) {
+		w, _ := NewShardedExponential[int](0.1, 2)
+		assertInDelta(t, 0.19, w.shards[0].w.alpha, 1e-12)
+	})
+
+	t.Run("approximates exponential", func(t *testing.T) {
+		const alpha = 0.01
+		w, _ := NewShardedExponential[float64](alpha, 8)
+		e := Exponential[float64](alpha)
+		for i := 0; i < 5000; i++ {
+			v := 0.0
+			if i >= 2500 {
+				// Step change
+				v = 1.0
+			}
+			w.Put(v)
+			e.Put(v)
+			if !assertLessOrEqual(t, math.Abs(w.Mean()-e.Mean()), 0.1, "mean should be close") {
+				break
+			}
+		}
+		assertEqual(t, 5000, w.Size())
+		assertEqual(t, 0.0, w.Min())
+		assertEqual(t, 1.0, w.Max())
+	})
+
+	t.Run("concurrent puts", func(t *testing.T) {
+		w, _ := NewShardedExponential[int](0.1, 4)
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range 1000 {
+					w.Put(g*1000 + i)
+				}
+				w.Mean()
+			}()
+		}
+		wg.Wait()
+		assertEqual(t, 8000, w.Size())
+		assertEqual(t, 0, w.Min())
+		assertEqual(t, 7999, w.Max())
+	})
+}
+
+func Test_NewShardedExponential(t *testing.T) {
+	for _, alpha := range []float64{-0.1, 0, 1.5, math.NaN()} {
+		w, err := NewShardedExponential[int](alpha, 2)
+		assertEqual(t, true, w == nil)
+		assertEqual(t, true, errors.Is(err, ErrInvalidAlpha), fmt.Sprint(alpha))
+	}
+
+	for _, shards := range []int{-1, 0} {
+		w, err := NewShardedExponential[int](0.1, shards)
+		assertEqual(t, true, w == nil)
+		assertEqual(t, true, errors.Is(err, ErrInvalidShards), fmt.Sprint(shards))
+	}
+
+	w, err := NewShardedExponential[int](1, 1)
+	assertNil(t, err)
+	assertEqual(t, 1, len(w.shards))
+}
@@ -0,0 +1,407 @@
+package mwnd
+
+import (
+	"fmt"
+	"iter"
+	"math"
+)
+
+const (
+	// sketchSlices is the number of slices in a SketchWindow, which determines how
+	// precisely the window follows its capacity.
+	sketchSlices = 16
+
+	// sketchMaxBins is the maximum number of bins in each store of a slice. At 1%
+	// relative accuracy, 2048 bins cover about 18 orders of magnitude before the
+	// lowest bins are collapsed together.
+	sketchMaxBins = 2048
+)
+
+// SketchWindow approximates the statistics of a fixed number of the most recent
+// values in bounded memory. Quantiles are estimated with a relative error guarantee,
+// following the DDSketch algorithm, while Min, Max, Mean, and Variance are exact.
+//
+// Values are grouped into 16 slices, each with its own sketch and room for 1/16 of
+// capacity values, rounded up or down so that the slices hold exactly capacity values
+// in total. The oldest slice is evicted as a whole once all slices are full. As a
+// result, the Size of a full window varies between about 15/16 of capacity and
+// capacity rather than being constant.
+// Memory usage is bounded by 16 slices of 2 stores of 2048 bins each, regardless of
+// capacity, so SketchWindow suits windows of many millions of values for which
+// FixedWindow would use too much memory.
+type SketchWindow[T Numeric] struct {
+	slices   []sketchSlice[T]
+	capacity int
+
+	// i is the index of the slice that receives new values
+	i int
+
+	mapping ddMapping
+
+	// scratch holds merged bin counts during Quantile to avoid allocations
+	scratch []uint64
+}
+
+type sketchSlice[T Numeric] struct {
+	moments
+	min, max T
+	sketch   ddSketch
+}
+
+// enforce compliance with interface
+var _ Window[float64] = (*SketchWindow[float64])(nil)
+
+// NewSketch initializes a moving window of approximately capacity values whose
+// quantiles are accurate within the relative error relativeAccuracy, such as 0.01
+// for 1%. It returns an error wrapping [ErrInvalidCapacity] if the capacity is
+// invalid, as for [NewFixed], or [ErrInvalidAccuracy] if relativeAccuracy is not
+// between 0.0 and 1.0, exclusive.
+func NewSketch[T Numeric](capacity int, relativeAccuracy float64) (*SketchWindow[T], error) {
+	if err := validCapacity(capacity); err != nil {
+		return nil, err
+	}
+	if err := validAccuracy(relativeAccuracy); err != nil {
+		return nil, err
+	}
+
+	n := min(capacity, sketchSlices)
+	return &SketchWindow[T]{
+		slices:   make([]sketchSlice[T], n),
+		capacity: capacity,
+		mapping:  newDDMapping(relativeAccuracy),
+	}, nil
+}
+
+// validAccuracy returns an error wrapping [ErrInvalidAccuracy] if relativeAccuracy is
+// not between 0.0 and 1.0, exclusive, which excludes NaN.
+func validAccuracy(relativeAccuracy float64) error {
+	if !(relativeAccuracy > 0 && relativeAccuracy < 1) {
+		return fmt.Errorf("%w: %v", ErrInvalidAccuracy, relativeAccuracy)
+	}
+	return nil
+}
+
+// sliceCapacity returns the number of values that the slice i holds once full, which
+// spreads the remainder of dividing capacity among the slices across the first slices.
+func (w *SketchWindow[T]) sliceCapacity(i int) int {
+	n := len(w.slices)
+	size := w.capacity / n
+	if i < w.capacity%n {
+		size++
+	}
+	return size
+}
+
+// Size returns the number of values in the Window.
+//
+// Time complexity of O(1).
+func (w *SketchWindow[T]) Size() int {
+	n := 0
+	for i := range w.slices {
+		n += w.slices[i].n
+	}
+	return n
+}
+
+// Min returns the lowest value in the Window.
+// If the Window has no values, then it returns the zero value.
+//
+// Time complexity of O(1).
+func (w *SketchWindow[T]) Min() T {
+	var v T
+	first := true
+	for i := range w.slices {
+		if s := &w.slices[i]; s.n > 0 {
+			if first || s.min < v {
+				v = s.min
+			}
+			first = false
+		}
+	}
+	return v
+}
+
+// Max returns the highest value in the Window.
+// If the Window has no values, then it returns the zero value.
+//
+// Time complexity of O(1).
+func (w *SketchWindow[T]) Max() T {
+	var v T
+	first := true
+	for i := range w.slices {
+		if s := &w.slices[i]; s.n > 0 {
+			if first || s.max > v {
+				v = s.max
+			}
+			first = false
+		}
+	}
+	return v
+}
+
+func (w *SketchWindow[T]) moments() moments {
+	var m moments
+	for i := range w.slices {
+		m.merge(w.slices[i].moments)
+	}
+	return m
+}
+
+// Mean returns the arithmetic mean of all values in the Window.
+// If the Window has no values, then it returns 0.0.
+//
+// Time complexity of O(1).
+func (w *SketchWindow[T]) Mean() float64 {
+	return w.moments().mean
+}
+
+// Variance returns the population variance of all values in the Window.
+// If the Window has no values, then it returns 0.0.
+//
+// Time complexity of O(1).
+func (w *SketchWindow[T]) Variance() float64 {
+	return w.moments().variance()
+}
+
+// Quantile returns an estimate of the value for which the probability of another value
+// being less than or equal to that value is q. The estimate is within the relative
+// accuracy of the value that [FixedWindow.Quantile] would return for the same values,
+// unless the lowest bins of the sketch have been collapsed.
+//
+// Time complexity of O(b), where b is the number of bins.
+func (w *SketchWindow[T]) Quantile(q float64) T {
+	if !validQuantile(q) {
+		panic("q must be between 0.0 and 1.0, inclusive")
+	}
+
+	n := w.Size()
+	if n == 0 {
+		return 0
+	}
+
+	sketches := func(yield func(*ddSketch) bool) {
+		for i := range w.slices {
+			if !yield(&w.slices[i].sketch) {
+				return
+			}
+		}
+	}
+
+	v, ok := w.mapping.quantile(sketches, uint64(quantileRank(n, q)), &w.scratch)
+	if !ok {
+		return w.Max()
+	}
+	return clampEstimate(v, w.Min(), w.Max())
+}
+
+// QuantileE is like [SketchWindow.Quantile], but returns an error wrapping
+// [ErrInvalidQuantile] instead of panicking if q is outside of the range 0.0 to 1.0,
+// inclusive.
+func (w *SketchWindow[T]) QuantileE(q float64) (T, error) {
+	if !validQuantile(q) {
+		var zero T
+		return zero, fmt.Errorf("%w: %v", ErrInvalidQuantile, q)
+	}
+	return w.Quantile(q), nil
+}
+
+// clampEstimate converts an estimate to T within the exact bounds lo and hi.
+func clampEstimate[T Numeric](v float64, lo, hi T) T {
+	v = min(max(v, float64(lo)), float64(hi))
+	if isFloat[T]() {
+		return T(v)
+	}
+	return T(math.Round(v))
+}
+
+// Put adds a new value to the Window, first evicting the oldest slice if all slices
+// are full.
+//
+// Time complexity of O(1), except when the range of bins grows or collapses.
+func (w *SketchWindow[T]) Put(v T) {
+	s := &w.slices[w.i]
+	if s.n == w.sliceCapacity(w.i) {
+		w.i = (w.i + 1) % len(w.slices)
+		s = &w.slices[w.i]
+		s.reset()
+	}
+
+	if s.n == 0 {
+		s.min, s.max = v, v
+	}
+
+	s.min = min(s.min, v)
+	s.max = max(s.max, v)
+	s.add(float64(v))
+	s.sketch.add(w.mapping, float64(v))
+}
+
+func (s *sketchSlice[T]) reset() {
+	s.moments = moments{}
+	s.sketch.reset()
+}
+
+// ddMapping maps values to the bins of a ddSketch so that every value in a bin is
+// within the relative accuracy of the estimate for the bin.
+type ddMapping struct {
+	// gamma is the ratio between the bounds of each bin
+	gamma, logGamma float64
+}
+
+func newDDMapping(relativeAccuracy float64) ddMapping {
+	gamma := (1 + relativeAccuracy) / (1 - relativeAccuracy)
+	return ddMapping{gamma: gamma, logGamma: math.Log(gamma)}
+}
+
+// key returns the index of the bin for a positive value v.
+func (m ddMapping) key(v float64) int {
+	return int(math.Ceil(math.Log(v) / m.logGamma))
+}
+
+// value returns the estimate of all values in the bin with the given key, which is
+// within the relative accuracy of every value in the bin.
+func (m ddMapping) value(key int) float64 {
+	return 2 * math.Pow(m.gamma, float64(key)) / (m.gamma + 1)
+}
+
+// quantile returns the estimate of the value with the 1-indexed rank among all values
+// counted by sketches, which must all use this mapping. Merged bin counts are stored in
+// scratch to avoid allocations. It returns false if rank exceeds the number of values.
+func (m ddMapping) quantile(sketches iter.Seq[*ddSketch], rank uint64, scratch *[]uint64) (float64, bool) {
+	// Negative values, from the largest magnitude to the smallest
+	offset, bins := mergeStores(sketches, func(s *ddSketch) *ddStore { return &s.neg }, scratch)
+	for i := len(bins) - 1; i >= 0; i-- {
+		if bins[i] >= rank {
+			return -m.value(offset + i), true
+		}
+		rank -= bins[i]
+	}
+
+	var zeros uint64
+	for s := range sketches {
+		zeros += s.zeros
+	}
+	if zeros >= rank {
+		return 0, true
+	}
+	rank -= zeros
+
+	offset, bins = mergeStores(sketches, func(s *ddSketch) *ddStore { return &s.pos }, scratch)
+	for i := range bins {
+		if bins[i] >= rank {
+			return m.value(offset + i), true
+		}
+		rank -= bins[i]
+	}
+
+	return 0, false
+}
+
+// mergeStores sums the bins of the store selected from each sketch into scratch,
+// returning the key of the first bin and the merged bins.
+func mergeStores(sketches iter.Seq[*ddSketch], store func(*ddSketch) *ddStore, scratch *[]uint64) (int, []uint64) {
+	lo, hi := math.MaxInt, math.MinInt
+	for sk := range sketches {
+		if s := store(sk); len(s.bins) > 0 {
+			lo = min(lo, s.offset)
+			hi = max(hi, s.offset+len(s.bins)-1)
+		}
+	}
+
+	if lo > hi {
+		return 0, nil
+	}
+
+	*scratch = append((*scratch)[:0], make([]uint64, hi-lo+1)...)
+	for sk := range sketches {
+		s := store(sk)
+		for j, c := range s.bins {
+			(*scratch)[s.offset-lo+j] += c
+		}
+	}
+
+	return lo, *scratch
+}
+
+// ddSketch counts values in bins whose bounds grow geometrically, following the
+// DDSketch algorithm. Sketches with the same mapping merge by summing their bins.
+type ddSketch struct {
+	// pos and neg hold counts of positive values and of the magnitudes of
+	// negative values, respectively
+	pos, neg ddStore
+	zeros    uint64
+}
+
+func (s *ddSketch) add(m ddMapping, v float64) {
+	switch {
+	case v > 0:
+		s.pos.add(m.key(v))
+	case v < 0:
+		s.neg.add(m.key(-v))
+	default:
+		s.zeros++
+	}
+}
+
+func (s *ddSketch) reset() {
+	s.pos.reset()
+	s.neg.reset()
+	s.zeros = 0
+}
+
+// ddStore counts values in contiguous bins by key, retaining at most sketchMaxBins
+// bins by collapsing the lowest keys together.
+type ddStore struct {
+	bins []uint64
+
+	// offset is the key of bins[0]
+	offset int
+}
+
+func (s *ddStore) reset() {
+	s.bins = s.bins[:0]
+	s.offset = 0
+}
+
+func (s *ddStore) add(key int) {
+	if len(s.bins) == 0 {
+		s.bins = append(s.bins, 1)
+		s.offset = key
+		return
+	}
+
+	lo, hi := min(s.offset, key), max(s.offset+len(s.bins)-1, key)
+	if hi-lo+1 > sketchMaxBins {
+		lo = hi - sketchMaxBins + 1
+		key = max(key, lo)
+	}
+
+	switch {
+	case lo < s.offset:
+		// Prepend empty bins for lower keys
+		grow := s.offset - lo
+		s.bins = append(s.bins, make([]uint64, grow)...)
+		copy(s.bins[grow:], s.bins)
+		clear(s.bins[:grow])
+	case lo > s.offset:
+		// Collapse the lowest bins into the new lowest bin
+		drop := min(lo-s.offset, len(s.bins))
+		var sum uint64
+		for _, c := range s.bins[:drop] {
+			sum += c
+		}
+		s.bins = append(s.bins[:0], s.bins[drop:]...)
+		if len(s.bins) == 0 {
+			// Every bin was below the new lowest key
+			s.bins = append(s.bins, 0)
+		}
+		s.bins[0] += sum
+	}
+	s.offset = lo
+
+	if n := hi - lo + 1; n > len(s.bins) {
+		s.bins = append(s.bins, make([]uint64, n-len(s.bins))...)
+	}
+
+	s.bins[key-lo]++
+}
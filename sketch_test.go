@@ -0,0 +1,197 @@
+package mwnd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func Test_sketch_empty(t *testing.T) {
+	w, _ := NewSketch[int](100, 0.01)
+	assertEqual(t, 0, w.Size())
+	assertEqual(t, 0, w.Min())
+	assertEqual(t, 0, w.Max())
+	assertEqual(t, 0.0, w.Mean())
+	assertEqual(t, 0.0, w.Variance())
+	assertEqual(t, 0, w.Quantile(0.5))
+}
+
+func Test_sketch_eviction(t *testing.T) {
+	w, _ := NewSketch[int](32, 0.01)
+	for v := range 32 {
+		w.Put(v)
+	}
+	assertEqual(t, 32, w.Size())
+	assertEqual(t, 0, w.Min())
+
+	w.Put(32)
+	assertEqual(t, 31, w.Size(), "should evict the oldest slice")
+	assertEqual(t, 2, w.Min())
+	assertEqual(t, 32, w.Max())
+	assertEqual(t, 17.0, w.Mean())
+}
+
+func Test_sketch_capacity(t *testing.T) {
+	// afterEvict is the size after the oldest slice is evicted for one more value
+	cases := []struct{ capacity, afterEvict int }{
+		{5, 5},
+		{16, 16},
+		{17, 16},
+		{31, 30},
+		{1000, 938},
+	}
+
+	for _, c := range cases {
+		w, _ := NewSketch[int](c.capacity, 0.01)
+		for v := range c.capacity {
+			w.Put(v)
+		}
+		assertEqual(t, c.capacity, w.Size(), fmt.Sprint(c.capacity), "should hold the full capacity")
+
+		w.Put(c.capacity)
+		assertEqual(t, c.afterEvict, w.Size(), fmt.Sprint(c.capacity))
+		assertEqual(t, c.capacity, w.Max(), fmt.Sprint(c.capacity))
+	}
+}
+
+func Test_sketch_Quantile(t *testing.T) {
+	const (
+		capacity = 1600
+		accuracy = 0.01
+	)
+
+	w, _ := NewSketch[float64](capacity, accuracy)
+	values := make([]float64, 0, capacity)
+	for i := range 10000 {
+		// Mix of negative, zero, and positive values over several orders of magnitude
+		v := math.Exp(rand.NormFloat64()*3) - 1
+		if i%10 == 0 {
+			v = 0
+		}
+
+		if len(values) == capacity {
+			values = values[capacity/sketchSlices:]
+		}
+		values = append(values, v)
+		w.Put(v)
+	}
+
+	if !assertEqual(t, len(values), w.Size()) {
+		return
+	}
+	assertEqual(t, slices.Min(values), w.Min())
+	assertEqual(t, slices.Max(values), w.Max())
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	assertInDelta(t, sum/float64(len(values)), w.Mean(), 1e-9)
+
+	slices.Sort(values)
+	for _, q := range []float64{0, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 1} {
+		rank := max(1, int(math.Ceil(float64(len(values))*q)))
+		want := values[rank-1]
+		got := w.Quantile(q)
+		if !assertInDelta(t, want, got, math.Abs(want)*accuracy, "quantile should be within relative accuracy") {
+			t.Logf("q=%v", q)
+		}
+	}
+}
+
+func Test_sketch_integers(t *testing.T) {
+	w, _ := NewSketch[int](10, 0.01)
+	for _, v := range []int{3, 6, 7, 8, 8, 10, 13, 15, 16, 20} {
+		w.Put(v)
+	}
+	assertEqual(t, 3, w.Quantile(0))
+	assertEqual(t, 7, w.Quantile(0.25))
+	assertEqual(t, 8, w.Quantile(0.5))
+	assertEqual(t, 15, w.Quantile(0.75))
+	assertEqual(t, 20, w.Quantile(1))
+}
+
+func Test_ddStore_add(t *testing.T) {
+	var s ddStore
+	s.add(5)
+	s.add(3)
+	s.add(7)
+	s.add(5)
+	assertEqual(t, 3, s.offset)
+	assertEqual(t, true, slices.Equal([]uint64{1, 0, 2, 0, 1}, s.bins))
+
+	s.add(3 + sketchMaxBins)
+	assertEqual(t, sketchMaxBins, len(s.bins), "should retain at most the max bins")
+	assertEqual(t, 4, s.offset)
+	assertEqual(t, uint64(1), s.bins[0], "should collapse the lowest bin")
+	assertEqual(t, uint64(2), s.bins[1])
+
+	s.add(0)
+	assertEqual(t, uint64(2), s.bins[0], "should collapse lower keys into the lowest bin")
+
+	s.add(10 * sketchMaxBins)
+	assertEqual(t, sketchMaxBins, len(s.bins), "should collapse all bins after a large jump")
+	assertEqual(t, 9*sketchMaxBins+1, s.offset)
+	assertEqual(t, uint64(6), s.bins[0], "should collapse every value into the lowest bin")
+	assertEqual(t, uint64(1), s.bins[sketchMaxBins-1])
+}
+
+func Test_ddSketch_add_largeJump(t *testing.T) {
+	m := newDDMapping(0.001)
+	for _, sign := range []float64{1, -1} {
+		var s ddSketch
+		store := &s.pos
+		if sign < 0 {
+			store = &s.neg
+		}
+
+		s.add(m, sign*1)
+		s.add(m, sign*100)
+		s.add(m, sign*1e300)
+		assertEqual(t, sketchMaxBins, len(store.bins), fmt.Sprint(sign))
+		assertEqual(t, m.key(1e300)-sketchMaxBins+1, store.offset, fmt.Sprint(sign))
+		assertEqual(t, uint64(2), store.bins[0], fmt.Sprint(sign), "should collapse the lower values")
+		assertEqual(t, uint64(1), store.bins[sketchMaxBins-1], fmt.Sprint(sign))
+	}
+
+	// Values far apart should not panic in a window
+	w, _ := NewSketch[float64](1000, 0.001)
+	w.Put(1)
+	w.Put(100)
+	assertInDelta(t, 100, w.Quantile(1), 100*0.001)
+
+	wi, _ := NewSketch[int](100, 0.01)
+	wi.Put(1)
+	wi.Put(1 << 62)
+	wi.Put(-1 << 62)
+	assertEqual(t, 3, wi.Size())
+	assertInDelta(t, -1<<62, float64(wi.Quantile(0)), (1<<62)*0.01)
+}
+
+func Test_sketch_QuantileE(t *testing.T) {
+	w, _ := NewSketch[float64](100, 0.01)
+	w.Put(5)
+	v, err := w.QuantileE(0.5)
+	assertNil(t, err)
+	assertInDelta(t, 5, v, 0.05)
+
+	_, err = w.QuantileE(math.NaN())
+	assertEqual(t, true, errors.Is(err, ErrInvalidQuantile))
+}
+
+func Test_NewSketch(t *testing.T) {
+	for _, capacity := range []int{-1, 0} {
+		w, err := NewSketch[int](capacity, 0.01)
+		assertEqual(t, true, w == nil)
+		assertEqual(t, true, errors.Is(err, ErrInvalidCapacity))
+	}
+
+	for _, accuracy := range []float64{-0.01, 0, 1, 2, math.NaN()} {
+		w, err := NewSketch[int](100, accuracy)
+		assertEqual(t, true, w == nil)
+		assertEqual(t, true, errors.Is(err, ErrInvalidAccuracy), fmt.Sprint(accuracy))
+	}
+}
@@ -0,0 +1,149 @@
+package mwnd
+
+import (
+	"iter"
+	"slices"
+)
+
+// Clone returns a deep copy of the Window, which can be modified independently of the
+// original. Since nodes link to each other by index, the copied tree needs no remapping.
+// The copy has no quantile cursors and no OnEvict or OnChange functions, so that it
+// doesn't disturb secondary structures maintained alongside the original.
+//
+// Time complexity of O(n), where n is the capacity of the Window.
+func (t *FixedWindow[T]) Clone() *FixedWindow[T] {
+	c := *t
+	c.nodes = slices.Clone(t.nodes)
+	c.ring = t.ring.clone()
+	c.shared = false
+	c.cursors = nil
+	c.onEvict = nil
+	c.onChange = nil
+	return &c
+}
+
+// Snapshot returns a read-only copy of the current state of the Window, which is safe to
+// query from another goroutine while the Window continues to receive values.
+//
+// Unlike Clone, Snapshot doesn't copy the values of the Window. Instead, the Snapshot
+// shares them, and the next modification of the Window copies them first, so that the
+// Snapshot is unaffected. Taking several snapshots between modifications therefore
+// costs at most one copy, and taking a Snapshot never blocks on it.
+//
+// Time complexity of O(1), plus O(n) for the next modification of the Window, where n
+// is the capacity of the Window.
+func (t *FixedWindow[T]) Snapshot() FixedSnapshot[T] {
+	t.shared = true
+	c := *t
+	c.cursors = nil
+	c.onEvict = nil
+	c.onChange = nil
+	return FixedSnapshot[T]{w: &c}
+}
+
+// unshare copies the values of the Window if they are shared with a Snapshot, which
+// must be done before modifying them.
+func (t *FixedWindow[T]) unshare() {
+	if !t.shared {
+		return
+	}
+
+	t.nodes = slices.Clone(t.nodes)
+	t.ring = t.ring.clone()
+	t.shared = false
+}
+
+// FixedSnapshot is a read-only copy of a FixedWindow at a point in time. Its methods
+// behave the same as those of FixedWindow. Unlike FixedWindow, a FixedSnapshot may be
+// queried from multiple goroutines concurrently, since none of its methods modify it.
+type FixedSnapshot[T Numeric] struct {
+	w *FixedWindow[T]
+}
+
+// Size returns the number of values in the Snapshot.
+func (s FixedSnapshot[T]) Size() int { return s.w.Size() }
+
+// Min returns the lowest value in the Snapshot. See [FixedWindow.Min].
+func (s FixedSnapshot[T]) Min() T { return s.w.Min() }
+
+// Max returns the highest value in the Snapshot. See [FixedWindow.Max].
+func (s FixedSnapshot[T]) Max() T { return s.w.Max() }
+
+// Mean returns the arithmetic mean of the values in the Snapshot. See [FixedWindow.Mean].
+func (s FixedSnapshot[T]) Mean() float64 { return s.w.Mean() }
+
+// Variance returns the population variance of the values in the Snapshot.
+// See [FixedWindow.Variance].
+func (s FixedSnapshot[T]) Variance() float64 { return s.w.Variance() }
+
+// Quantile returns the value of the quantile q. See [FixedWindow.Quantile].
+func (s FixedSnapshot[T]) Quantile(q float64) T { return s.w.Quantile(q) }
+
+// QuantileE returns the value of the quantile q or an error. See [FixedWindow.QuantileE].
+func (s FixedSnapshot[T]) QuantileE(q float64) (T, error) { return s.w.QuantileE(q) }
+
+// Quantiles appends the value of each quantile in qs to dst. See [FixedWindow.Quantiles].
+func (s FixedSnapshot[T]) Quantiles(dst []T, qs ...float64) ([]T, error) {
+	return s.w.Quantiles(dst, qs...)
+}
+
+// Kth returns the k-th lowest value. See [FixedWindow.Kth].
+func (s FixedSnapshot[T]) Kth(k int) (T, bool) { return s.w.Kth(k) }
+
+// KthLargest returns the k-th highest value. See [FixedWindow.KthLargest].
+func (s FixedSnapshot[T]) KthLargest(k int) (T, bool) { return s.w.KthLargest(k) }
+
+// Range returns an iterator over the values with ranks i through j. See [FixedWindow.Range].
+func (s FixedSnapshot[T]) Range(i, j int) iter.Seq[T] { return s.w.Range(i, j) }
+
+// IQR returns the interquartile range. See [FixedWindow.IQR].
+func (s FixedSnapshot[T]) IQR() T { return s.w.IQR() }
+
+// IsOutlier classifies v against the Tukey fences. See [FixedWindow.IsOutlier].
+func (s FixedSnapshot[T]) IsOutlier(v T, k float64) (low, high bool) { return s.w.IsOutlier(v, k) }
+
+// At returns the value that was Put age values before the newest. See [FixedWindow.At].
+func (s FixedSnapshot[T]) At(age int) (T, bool) { return s.w.At(age) }
+
+// Newest returns the newest value. See [FixedWindow.Newest].
+func (s FixedSnapshot[T]) Newest() (T, bool) { return s.w.Newest() }
+
+// Oldest returns the oldest value. See [FixedWindow.Oldest].
+func (s FixedSnapshot[T]) Oldest() (T, bool) { return s.w.Oldest() }
+
+// Values appends all values to dst from oldest to newest. See [FixedWindow.Values].
+func (s FixedSnapshot[T]) Values(dst []T) []T { return s.w.Values(dst) }
+
+// Summary returns the statistics of the Snapshot. See [FixedWindow.Summary].
+func (s FixedSnapshot[T]) Summary(qs ...float64) Summary[T] { return s.w.Summary(qs...) }
+
+// Snapshot returns a read-only copy of the current state of the Window.
+//
+// Time complexity of O(1).
+func (w *ExponentialWindow[T]) Snapshot() ExponentialSnapshot[T] {
+	return ExponentialSnapshot[T]{w: *w}
+}
+
+// ExponentialSnapshot is a read-only copy of an ExponentialWindow at a point in time.
+// Its methods behave the same as those of ExponentialWindow.
+type ExponentialSnapshot[T Numeric] struct {
+	w ExponentialWindow[T]
+}
+
+// Size returns the number of values added to the Window before the Snapshot.
+func (s ExponentialSnapshot[T]) Size() int { return s.w.Size() }
+
+// Min returns the lowest value observed. See [ExponentialWindow.Min].
+func (s ExponentialSnapshot[T]) Min() T { return s.w.Min() }
+
+// Max returns the highest value observed. See [ExponentialWindow.Max].
+func (s ExponentialSnapshot[T]) Max() T { return s.w.Max() }
+
+// Mean returns the exponentially-weighted moving average. See [ExponentialWindow.Mean].
+func (s ExponentialSnapshot[T]) Mean() float64 { return s.w.Mean() }
+
+// Variance returns the exponentially-weighted moving variance. See [ExponentialWindow.Variance].
+func (s ExponentialSnapshot[T]) Variance() float64 { return s.w.Variance() }
+
+// Summary returns the statistics of the Snapshot. See [ExponentialWindow.Summary].
+func (s ExponentialSnapshot[T]) Summary(qs ...float64) Summary[T] { return s.w.Summary(qs...) }
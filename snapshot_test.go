@@ -0,0 +1,118 @@
+package mwnd
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func Test_fixed_Clone(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithStats(MinMax)}} {
+		tr := Fixed[int](4, opts...)
+		tr.PutAll([]int{5, 1, 3})
+		var evicted int
+		tr.OnEvict(func(old int) { evicted++ })
+
+		c := tr.Clone()
+		c.Put(9)
+		c.Put(0) // replaces 5
+		assertEqual(t, 0, evicted, "clone should not share hooks")
+		assertEqual(t, true, slices.Equal([]int{5, 1, 3}, tr.Values(nil)), "original should be unchanged")
+		assertEqual(t, 1, tr.Min())
+		assertEqual(t, 5, tr.Max())
+
+		assertEqual(t, true, slices.Equal([]int{1, 3, 9, 0}, c.Values(nil)))
+		assertEqual(t, 0, c.Min())
+		assertEqual(t, 9, c.Max())
+		if c.tracks(Quantiles) {
+			assertRedBlackProperties(t, c)
+			assertRedBlackProperties(t, tr)
+		}
+	}
+}
+
+func Test_fixed_Snapshot(t *testing.T) {
+	tr := makeFixed(3, 6, 7, 8, 8, 10, 13, 15, 16, 20)
+	s := tr.Snapshot()
+	tr.Put(100)
+
+	assertEqual(t, 10, s.Size())
+	assertEqual(t, 3, s.Min())
+	assertEqual(t, 20, s.Max())
+	assertEqual(t, 8, s.Quantile(0.5))
+	v, _ := s.Oldest()
+	assertEqual(t, 3, v)
+
+	// Concurrent reads and writes to the Window should be safe, as checked by the
+	// race detector
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q := 0.0; q <= 1.0; q += 0.1 {
+				s.Quantile(q)
+			}
+			s.Summary(0.5, 0.9)
+			for range s.Range(1, 10) {
+			}
+		}()
+	}
+	for v := range 100 {
+		tr.Put(v)
+	}
+	wg.Wait()
+
+	assertEqual(t, true, slices.Equal([]int{3, 6, 7, 8, 8, 10, 13, 15, 16, 20}, s.Values(nil)))
+}
+
+func Test_fixed_Snapshot_copyOnWrite(t *testing.T) {
+	modify := map[string]func(tr *FixedWindow[int]){
+		"Put":           func(tr *FixedWindow[int]) { tr.Put(0) },
+		"UndoLast":      func(tr *FixedWindow[int]) { tr.UndoLast() },
+		"ReplaceNewest": func(tr *FixedWindow[int]) { tr.ReplaceNewest(0) },
+		"Reset":         func(tr *FixedWindow[int]) { tr.Reset() },
+		"PutAll":        func(tr *FixedWindow[int]) { tr.PutAll([]int{9, 8, 7, 6}) },
+	}
+
+	for _, opts := range [][]Option{nil, {WithStats(MinMax)}} {
+		for name, f := range modify {
+			tr := Fixed[int](4, opts...)
+			tr.PutAll([]int{5, 1, 3})
+			tr.Put(4)
+			s := tr.Snapshot()
+			s2 := tr.Snapshot()
+
+			f(tr)
+			for _, s := range []FixedSnapshot[int]{s, s2} {
+				assertEqual(t, true, slices.Equal([]int{5, 1, 3, 4}, s.Values(nil)), name)
+				assertEqual(t, 1, s.Min(), name)
+				assertEqual(t, 5, s.Max(), name)
+				assertEqual(t, 3.25, s.Mean(), name)
+			}
+
+			// The window should go on after copying its values
+			tr.Put(2)
+			newest, _ := tr.Newest()
+			assertEqual(t, 2, newest, name)
+			if tr.tracks(Quantiles) {
+				assertRedBlackProperties(t, tr)
+			}
+		}
+	}
+
+	tr := Fixed[int](1 << 16)
+	allocs := testing.AllocsPerRun(10, func() { tr.Snapshot() })
+	assertLessOrEqual(t, allocs, 1, "should not copy the values")
+}
+
+func Test_exponential_Snapshot(t *testing.T) {
+	w := Exponential[float64](0.5)
+	w.Put(2)
+	s := w.Snapshot()
+	w.Put(4)
+
+	assertEqual(t, 1, s.Size())
+	assertEqual(t, 2.0, s.Mean())
+	assertEqual(t, 3.0, w.Mean())
+}
@@ -0,0 +1,157 @@
+package mwnd
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// StreamOption configures when Stream and StreamSeq emit a Summary.
+type StreamOption func(*streamOptions)
+
+type streamOptions struct {
+	every     int
+	interval  time.Duration
+	quantiles []float64
+}
+
+// EmitEvery configures a stream to emit a Summary after every n values.
+func EmitEvery(n int) StreamOption {
+	return func(o *streamOptions) {
+		o.every = n
+	}
+}
+
+// EmitInterval configures a stream to emit a Summary every period of length d, whether
+// or not any values arrived during the period.
+func EmitInterval(d time.Duration) StreamOption {
+	return func(o *streamOptions) {
+		o.interval = d
+	}
+}
+
+// EmitQuantiles configures a stream to include the values of the quantiles qs in each
+// Summary, if the window supports quantiles.
+func EmitQuantiles(qs ...float64) StreamOption {
+	return func(o *streamOptions) {
+		o.quantiles = qs
+	}
+}
+
+// stream holds the state shared by Stream and StreamSeq.
+type stream[T Numeric] struct {
+	streamOptions
+	w   Window[T]
+	out chan<- Summary[T]
+
+	// pending is the number of values Put since the last Summary was emitted
+	pending int
+}
+
+func newStream[T Numeric](w Window[T], out chan<- Summary[T], opts []StreamOption) *stream[T] {
+	s := &stream[T]{w: w, out: out}
+	for _, opt := range opts {
+		opt(&s.streamOptions)
+	}
+	return s
+}
+
+// ticks returns a channel that receives on each interval, or nil if there is no interval,
+// along with a function to stop the ticker.
+func (s *stream[T]) ticks() (<-chan time.Time, func()) {
+	if s.interval <= 0 {
+		return nil, func() {}
+	}
+	t := time.NewTicker(s.interval)
+	return t.C, t.Stop
+}
+
+// put adds v to the window, emitting a Summary if EmitEvery is due.
+func (s *stream[T]) put(ctx context.Context, v T) error {
+	s.w.Put(v)
+	s.pending++
+	if s.every > 0 && s.pending >= s.every {
+		return s.emit(ctx)
+	}
+	return nil
+}
+
+// emit sends a Summary of the window to out, unless ctx is done first.
+func (s *stream[T]) emit(ctx context.Context) error {
+	s.pending = 0
+	select {
+	case s.out <- summarize(s.w, s.quantiles):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// finish emits a final Summary if any values arrived since the last one.
+func (s *stream[T]) finish(ctx context.Context) error {
+	if s.pending == 0 {
+		return nil
+	}
+	return s.emit(ctx)
+}
+
+// Stream Puts each value received from in into w, sending a Summary of w to out as
+// configured by opts, until in is closed or ctx is done. A Summary is produced as
+// described for [NewPublisher]. When in is closed, Stream sends a final Summary if any
+// values arrived since the last one and returns nil. If neither [EmitEvery] nor
+// [EmitInterval] is given, then that final Summary is the only one.
+//
+// If ctx is done first, then Stream returns ctx.Err() without a final Summary. Sends to
+// out block, so a slow receiver applies back pressure to in. Stream doesn't close out.
+//
+// The window must not be used by other goroutines until Stream returns.
+func Stream[T Numeric](ctx context.Context, in <-chan T, w Window[T], out chan<- Summary[T], opts ...StreamOption) error {
+	s := newStream(w, out, opts)
+	ticks, stop := s.ticks()
+	defer stop()
+
+	for {
+		select {
+		case v, ok := <-in:
+			if !ok {
+				return s.finish(ctx)
+			}
+			if err := s.put(ctx, v); err != nil {
+				return err
+			}
+		case <-ticks:
+			if err := s.emit(ctx); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// StreamSeq is like [Stream], but Puts each value of seq into w until seq ends. Since
+// the values are pulled from seq, the context and ticker are checked between values,
+// so a Summary for [EmitInterval] may be delayed while seq is waiting for a value.
+func StreamSeq[T Numeric](ctx context.Context, seq iter.Seq[T], w Window[T], out chan<- Summary[T], opts ...StreamOption) error {
+	s := newStream(w, out, opts)
+	ticks, stop := s.ticks()
+	defer stop()
+
+	for v := range seq {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticks:
+			if err := s.emit(ctx); err != nil {
+				return err
+			}
+		default:
+		}
+
+		if err := s.put(ctx, v); err != nil {
+			return err
+		}
+	}
+
+	return s.finish(ctx)
+}
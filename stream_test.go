@@ -0,0 +1,113 @@
+package mwnd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_Stream(t *testing.T) {
+	t.Run("emit every", func(t *testing.T) {
+		in := make(chan int)
+		out := make(chan Summary[int], 10)
+		go func() {
+			for i := 1; i <= 7; i++ {
+				in <- i
+			}
+			close(in)
+		}()
+
+		err := Stream(context.Background(), in, Fixed[int](3), out, EmitEvery(3), EmitQuantiles(0.5))
+		assertNil(t, err)
+		close(out)
+
+		var summaries []Summary[int]
+		for s := range out {
+			summaries = append(summaries, s)
+		}
+		assertEqual(t, 3, len(summaries), "should emit twice and once more at the end")
+		assertEqual(t, 2.0, summaries[0].Mean)
+		assertEqual(t, 5.0, summaries[1].Mean)
+		assertEqual(t, 6.0, summaries[2].Mean)
+		v, ok := summaries[2].Quantile(0.5)
+		assertEqual(t, true, ok)
+		assertEqual(t, 6, v)
+	})
+
+	t.Run("final summary only", func(t *testing.T) {
+		in := make(chan float64, 3)
+		out := make(chan Summary[float64], 3)
+		in <- 1
+		in <- 3
+		close(in)
+
+		assertNil(t, Stream(context.Background(), in, Exponential[float64](0.5), out))
+		assertEqual(t, 1, len(out))
+		assertEqual(t, 2.0, (<-out).Mean)
+	})
+
+	t.Run("emit interval", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		in := make(chan int, 1)
+		out := make(chan Summary[int])
+		in <- 5
+		done := make(chan error)
+		go func() {
+			done <- Stream(ctx, in, Fixed[int](3), out, EmitInterval(time.Millisecond))
+		}()
+
+		s := <-out
+		for s.Count == 0 {
+			s = <-out
+		}
+		assertEqual(t, 5, s.Max)
+		<-out // emits even without new values
+
+		cancel()
+		assertEqual(t, true, errors.Is(<-done, context.Canceled))
+	})
+
+	t.Run("cancel while sending", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		in := make(chan int, 1)
+		in <- 1
+		close(in)
+		cancel()
+
+		// Nothing receives from out, so only the context can end the final send
+		err := Stream(ctx, in, Fixed[int](3), make(chan Summary[int]))
+		assertEqual(t, true, errors.Is(err, context.Canceled))
+	})
+}
+
+func Test_StreamSeq(t *testing.T) {
+	t.Run("emit every", func(t *testing.T) {
+		out := make(chan Summary[int], 10)
+		err := StreamSeq(context.Background(), slices.Values([]int{1, 2, 3, 4}), Fixed[int](2), out, EmitEvery(2))
+		assertNil(t, err)
+		assertEqual(t, 2, len(out), "should not emit at the end without new values")
+		assertEqual(t, 1.5, (<-out).Mean)
+		assertEqual(t, 3.5, (<-out).Mean)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		out := make(chan Summary[int], 10)
+		seq := func(yield func(int) bool) {
+			for i := 0; ; i++ {
+				if i == 5 {
+					cancel()
+				}
+				if !yield(i) {
+					return
+				}
+			}
+		}
+
+		err := StreamSeq(ctx, seq, Fixed[int](2), out)
+		assertEqual(t, true, errors.Is(err, context.Canceled))
+		assertEqual(t, 0, len(out))
+	})
+}
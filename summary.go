@@ -0,0 +1,398 @@
+package mwnd
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Summary is a snapshot of the statistics of a window at a point in time.
+//
+// A Summary implements [slog.LogValuer] and [fmt.Stringer], and it encodes to JSON
+// with lowercase field names. Each of them leaves out missing statistics.
+type Summary[T Numeric] struct {
+	// Count is the number of values in the window.
+	Count    int
+	Min, Max T
+	Mean     float64
+
+	// Variance is the population variance, and StdDev is its square root.
+	Variance float64
+	StdDev   float64
+
+	// Quantiles holds the value of each requested quantile, in the order that
+	// they were requested.
+	Quantiles []QuantileValue[T]
+
+	// Missing identifies the statistics that the window couldn't provide, whose fields
+	// hold the zero value rather than real values: MinMax if the window doesn't track
+	// the minimum and maximum, such as a FixedWindow created with WithStats(Mean), and
+	// Quantiles if quantiles were requested from a window that doesn't track them.
+	Missing Stat
+}
+
+// has reports whether the Summary includes the statistic st.
+func (s Summary[T]) has(st Stat) bool {
+	return s.Missing&st == 0
+}
+
+// QuantileValue is the value of the quantile Q in a Summary.
+type QuantileValue[T Numeric] struct {
+	Q     float64 `json:"q"`
+	Value T       `json:"value"`
+}
+
+// quantileKey returns the name of the quantile q as a percentile, such as p50 for 0.5.
+func quantileKey(q float64) string {
+	return "p" + strconv.FormatFloat(q*100, 'g', -1, 64)
+}
+
+// LogValue implements [slog.LogValuer], logging the statistics as a group with each
+// quantile named as a percentile, such as p99 for 0.99.
+func (s Summary[T]) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 6+len(s.Quantiles))
+	attrs = append(attrs, slog.Int("count", s.Count))
+	if s.has(MinMax) {
+		attrs = append(attrs, slog.Any("min", s.Min), slog.Any("max", s.Max))
+	}
+	attrs = append(attrs,
+		slog.Float64("mean", s.Mean),
+		slog.Float64("variance", s.Variance),
+		slog.Float64("stddev", s.StdDev),
+	)
+	for _, qv := range s.Quantiles {
+		attrs = append(attrs, slog.Any(quantileKey(qv.Q), qv.Value))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// String implements [fmt.Stringer], formatting the statistics as space-separated
+// key=value pairs in the same form as LogValue.
+func (s Summary[T]) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "count=%d", s.Count)
+	if s.has(MinMax) {
+		fmt.Fprintf(&sb, " min=%v max=%v", s.Min, s.Max)
+	}
+	fmt.Fprintf(&sb, " mean=%v variance=%v stddev=%v", s.Mean, s.Variance, s.StdDev)
+	for _, qv := range s.Quantiles {
+		fmt.Fprintf(&sb, " %s=%v", quantileKey(qv.Q), qv.Value)
+	}
+	return sb.String()
+}
+
+// summaryJSON is the JSON encoding of a Summary, in which Min and Max are pointers so
+// that they can be left out when missing.
+type summaryJSON[T Numeric] struct {
+	Count     int                `json:"count"`
+	Min       *T                 `json:"min,omitempty"`
+	Max       *T                 `json:"max,omitempty"`
+	Mean      float64            `json:"mean"`
+	Variance  float64            `json:"variance"`
+	StdDev    float64            `json:"stddev"`
+	Quantiles []QuantileValue[T] `json:"quantiles,omitempty"`
+	Missing   []string           `json:"missing,omitempty"`
+}
+
+// statNames are the names of the statistics in the JSON encoding of a Summary.
+var statNames = []struct {
+	stat Stat
+	name string
+}{
+	{Mean, "mean"},
+	{Variance, "variance"},
+	{MinMax, "minmax"},
+	{Quantiles, "quantiles"},
+}
+
+// statByName returns the statistic with the given name in statNames.
+func statByName(name string) (Stat, bool) {
+	for _, sn := range statNames {
+		if sn.name == name {
+			return sn.stat, true
+		}
+	}
+	return 0, false
+}
+
+// MarshalJSON implements [json.Marshaler], leaving out min and max if they are missing.
+// Missing statistics are listed by name, such as "missing":["minmax","quantiles"].
+func (s Summary[T]) MarshalJSON() ([]byte, error) {
+	j := summaryJSON[T]{
+		Count:     s.Count,
+		Mean:      s.Mean,
+		Variance:  s.Variance,
+		StdDev:    s.StdDev,
+		Quantiles: s.Quantiles,
+	}
+	if s.has(MinMax) {
+		j.Min, j.Max = &s.Min, &s.Max
+	}
+	for _, sn := range statNames {
+		if !s.has(sn.stat) {
+			j.Missing = append(j.Missing, sn.name)
+		}
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (s *Summary[T]) UnmarshalJSON(data []byte) error {
+	var j summaryJSON[T]
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*s = Summary[T]{
+		Count:     j.Count,
+		Mean:      j.Mean,
+		Variance:  j.Variance,
+		StdDev:    j.StdDev,
+		Quantiles: j.Quantiles,
+	}
+	for _, name := range j.Missing {
+		st, ok := statByName(name)
+		if !ok {
+			return fmt.Errorf("mwnd: unknown missing statistic %q", name)
+		}
+		s.Missing |= st
+	}
+	if j.Min != nil && j.Max != nil {
+		s.Min, s.Max = *j.Min, *j.Max
+	} else {
+		s.Missing |= MinMax
+	}
+	return nil
+}
+
+// Quantile returns the value of the quantile q if it was included in the Summary.
+func (s Summary[T]) Quantile(q float64) (T, bool) {
+	for _, qv := range s.Quantiles {
+		if qv.Q == q {
+			return qv.Value, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// Merge combines the statistics of two Summaries as if their values had been
+// aggregated by a single window, using the parallel algorithm of Chan et al. for
+// the mean and variance. Quantiles can't be combined exactly, so the result has
+// no Quantiles. A statistic missing from either Summary is missing from the result.
+func (s Summary[T]) Merge(o Summary[T]) Summary[T] {
+	missing := s.Missing | o.Missing
+	var out Summary[T]
+	switch {
+	case o.Count == 0:
+		out = s
+	case s.Count == 0:
+		out = o
+	default:
+		m := moments{n: s.Count, mean: s.Mean, m2: s.Variance * float64(s.Count)}
+		m.merge(moments{n: o.Count, mean: o.Mean, m2: o.Variance * float64(o.Count)})
+		out = Summary[T]{
+			Count:    m.n,
+			Min:      min(s.Min, o.Min),
+			Max:      max(s.Max, o.Max),
+			Mean:     m.mean,
+			Variance: m.variance(),
+			StdDev:   math.Sqrt(m.variance()),
+		}
+	}
+
+	out.Quantiles = nil
+	out.Missing = missing
+	if !out.has(MinMax) {
+		var zero T
+		out.Min, out.Max = zero, zero
+	}
+	return out
+}
+
+// summaryStats returns the statistics that a FixedWindow must track to produce a
+// Summary with the given quantiles.
+func summaryStats(quantiles []float64) Stat {
+	if len(quantiles) == 0 {
+		return Mean | Variance | MinMax
+	}
+	return AllStats
+}
+
+// summarize returns the Summary of w, including the values of quantiles if w supports
+// them. If w has a method Summary(qs ...float64), such as FixedWindow, then it produces
+// the Summary. Otherwise, the Summary is assembled from the methods of Window, and the
+// quantiles are reported as Missing.
+func summarize[T Numeric](w Window[T], quantiles []float64) Summary[T] {
+	if w, ok := w.(interface{ Summary(...float64) Summary[T] }); ok {
+		return w.Summary(quantiles...)
+	}
+
+	s := Summary[T]{
+		Count:    w.Size(),
+		Min:      w.Min(),
+		Max:      w.Max(),
+		Mean:     w.Mean(),
+		Variance: w.Variance(),
+		StdDev:   math.Sqrt(w.Variance()),
+	}
+	if len(quantiles) > 0 {
+		s.Missing |= Quantiles
+	}
+	return s
+}
+
+// summaryEncodingVersion is the first byte of every binary-encoded Summary, so that
+// the format can evolve.
+const summaryEncodingVersion = 1
+
+// AppendBinary implements [encoding.BinaryAppender]. Integer values are encoded as
+// varints and floating-point values as 8 bytes, so the encoding of a Summary without
+// quantiles is typically 20 to 40 bytes. A Summary must be decoded with the same type
+// parameter T that it was encoded with.
+func (s Summary[T]) AppendBinary(b []byte) ([]byte, error) {
+	b = append(b, summaryEncodingVersion, byte(s.Missing))
+	b = binary.AppendUvarint(b, uint64(s.Count))
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(s.Mean))
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(s.Variance))
+	b = appendValue(b, s.Min)
+	b = appendValue(b, s.Max)
+	b = binary.AppendUvarint(b, uint64(len(s.Quantiles)))
+	for _, qv := range s.Quantiles {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(qv.Q))
+		b = appendValue(b, qv.Value)
+	}
+	return b, nil
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler]. See [Summary.AppendBinary]
+// for details of the encoding.
+func (s Summary[T]) MarshalBinary() ([]byte, error) {
+	return s.AppendBinary(nil)
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler]. It returns
+// [ErrInvalidEncoding] if data is not a valid encoding of a Summary.
+func (s *Summary[T]) UnmarshalBinary(data []byte) error {
+	d := decoder{b: data}
+	if d.readByte() != summaryEncodingVersion {
+		return ErrInvalidEncoding
+	}
+
+	var out Summary[T]
+	out.Missing = Stat(d.readByte())
+
+	out.Count = int(d.readUvarint())
+	out.Mean = d.readFloat64()
+	out.Variance = d.readFloat64()
+	out.StdDev = math.Sqrt(out.Variance)
+	out.Min = decodeValue[T](&d)
+	out.Max = decodeValue[T](&d)
+
+	n := d.readUvarint()
+	if d.err != nil || n > uint64(len(d.b)) {
+		return ErrInvalidEncoding
+	}
+
+	if n > 0 {
+		out.Quantiles = make([]QuantileValue[T], n)
+		for i := range out.Quantiles {
+			out.Quantiles[i].Q = d.readFloat64()
+			out.Quantiles[i].Value = decodeValue[T](&d)
+		}
+	}
+
+	if d.err != nil || len(d.b) > 0 {
+		return ErrInvalidEncoding
+	}
+
+	*s = out
+	return nil
+}
+
+// isFloat reports whether T is a floating-point type.
+func isFloat[T Numeric]() bool {
+	half := 0.5
+	return T(half) != 0
+}
+
+func appendValue[T Numeric](b []byte, v T) []byte {
+	if isFloat[T]() {
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(float64(v)))
+	}
+
+	// Converting through int64 preserves the bits of every integer type, including
+	// uint64 values that overflow int64.
+	return binary.AppendVarint(b, int64(v))
+}
+
+func decodeValue[T Numeric](d *decoder) T {
+	if isFloat[T]() {
+		return T(d.readFloat64())
+	}
+	return T(d.readVarint())
+}
+
+// decoder reads successive fields from b, recording the first error so that it
+// only needs to be checked once.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) readByte() byte {
+	if d.err != nil || len(d.b) < 1 {
+		d.err = ErrInvalidEncoding
+		return 0
+	}
+
+	v := d.b[0]
+	d.b = d.b[1:]
+	return v
+}
+
+func (d *decoder) readUvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = ErrInvalidEncoding
+		return 0
+	}
+
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) readVarint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = ErrInvalidEncoding
+		return 0
+	}
+
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) readFloat64() float64 {
+	if d.err != nil || len(d.b) < 8 {
+		d.err = ErrInvalidEncoding
+		return 0
+	}
+
+	v := math.Float64frombits(binary.LittleEndian.Uint64(d.b))
+	d.b = d.b[8:]
+	return v
+}
@@ -0,0 +1,238 @@
+package mwnd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func Test_summary_Quantile(t *testing.T) {
+	s := makeFixed(3, 6, 7, 8, 8, 10, 13, 15, 16, 20).Summary(0.9, 0.25)
+	assertEqual(t, 10, s.Count)
+	assertEqual(t, 3, s.Min)
+	assertEqual(t, 20, s.Max)
+	assertEqual(t, 10.6, s.Mean)
+
+	v, ok := s.Quantile(0.25)
+	assertEqual(t, true, ok)
+	assertEqual(t, 7, v)
+
+	v, ok = s.Quantile(0.9)
+	assertEqual(t, true, ok)
+	assertEqual(t, 16, v)
+
+	v, ok = s.Quantile(0.5)
+	assertEqual(t, false, ok, "should not include quantiles that weren't requested")
+	assertEqual(t, 0, v)
+
+	qs := []float64{1.5, 0.9, math.NaN(), 0.25}
+	s = makeFixed(3, 6, 7, 8, 8, 10, 13, 15, 16, 20).Summary(qs...)
+	assertEqual(t, true, slices.Equal([]QuantileValue[int]{{0.9, 16}, {0.25, 7}}, s.Quantiles), "should omit invalid quantiles")
+	assertEqual(t, 1.5, qs[0], "should not modify qs")
+}
+
+func Test_summary_StdDev(t *testing.T) {
+	f := makeFixed(2, 4, 4, 4, 5, 5, 7, 9).Summary()
+	assertEqual(t, 2.0, f.StdDev)
+
+	e := Exponential[int](0.5)
+	e.Put(1)
+	e.Put(3)
+	s := e.Summary(0.5)
+	assertEqual(t, 1.0, s.StdDev)
+	assertEqual(t, 0, len(s.Quantiles), "exponential window should have no quantiles")
+	assertEqual(t, Quantiles, s.Missing)
+}
+
+func Test_summary_String(t *testing.T) {
+	s := makeFixed(1, 2, 3, 4).Summary(0.5, 0.99)
+	assertEqual(t, "count=4 min=1 max=4 mean=2.5 variance=1.25 stddev=1.118033988749895 p50=2 p99=4", s.String())
+	assertEqual(t, "count=0 min=0 max=0 mean=0 variance=0 stddev=0", Summary[int]{}.String())
+}
+
+func Test_summary_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	logger.Info("stats", "window", makeFixed(1, 2, 3, 4).Summary(0.5))
+	assertEqual(t, "level=INFO msg=stats window.count=4 window.min=1 window.max=4 window.mean=2.5 "+
+		"window.variance=1.25 window.stddev=1.118033988749895 window.p50=2\n", buf.String())
+}
+
+func Test_summary_JSON(t *testing.T) {
+	want := makeFixed(1, 2, 3, 4).Summary(0.5)
+	b, err := json.Marshal(want)
+	if !assertNil(t, err) {
+		return
+	}
+	assertEqual(t, `{"count":4,"min":1,"max":4,"mean":2.5,"variance":1.25,"stddev":1.118033988749895,`+
+		`"quantiles":[{"q":0.5,"value":2}]}`, string(b))
+
+	var got Summary[int]
+	if !assertNil(t, json.Unmarshal(b, &got)) {
+		return
+	}
+	assertEqual(t, true, reflect.DeepEqual(want, got), fmt.Sprintf("want %+v, got %+v", want, got))
+
+	b, err = json.Marshal(Summary[int]{})
+	assertNil(t, err)
+	assertEqual(t, `{"count":0,"min":0,"max":0,"mean":0,"variance":0,"stddev":0}`, string(b), "should omit empty quantiles")
+}
+
+func Test_summary_Missing(t *testing.T) {
+	w := Fixed[int](3, WithStats(Mean))
+	w.Put(5)
+	s := w.Summary(0.5)
+	assertEqual(t, MinMax|Quantiles, s.Missing)
+	assertEqual(t, 0, len(s.Quantiles), "should leave out untracked quantiles")
+	assertEqual(t, "count=1 mean=5 variance=0 stddev=0", s.String())
+
+	b, err := json.Marshal(s)
+	assertNil(t, err)
+	assertEqual(t, `{"count":1,"mean":5,"variance":0,"stddev":0,"missing":["minmax","quantiles"]}`, string(b))
+
+	var got Summary[int]
+	assertNil(t, json.Unmarshal(b, &got))
+	assertEqual(t, true, reflect.DeepEqual(s, got), fmt.Sprintf("want %+v, got %+v", s, got))
+
+	err = json.Unmarshal([]byte(`{"count":1,"missing":["median"]}`), &got)
+	assertEqual(t, true, err != nil, "should reject unknown statistics")
+
+	s = Fixed[int](3, WithStats(Quantiles)).Summary(0.5)
+	assertEqual(t, Stat(0), s.Missing, "the tree should provide the min and max")
+
+	merged := makeFixed(1, 9).Summary().Merge(w.Summary())
+	assertEqual(t, MinMax, merged.Missing, "merge should keep missing statistics")
+	assertEqual(t, 0, merged.Max)
+	assertEqual(t, 5.0, merged.Mean)
+
+	s = summarize[int](Fixed[int](3, WithStats(Mean)), nil)
+	assertEqual(t, MinMax, s.Missing, "should not report quantiles missing unless requested")
+}
+
+func Test_summary_Merge(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		a := makeFixed(1, 2, 3).Summary(0.5)
+		var empty Summary[int]
+
+		got := a.Merge(empty)
+		assertEqual(t, 3, got.Count)
+		assertEqual(t, 2.0, got.Mean)
+		assertNil(t, got.Quantiles)
+
+		got = empty.Merge(a)
+		assertEqual(t, 3, got.Count)
+		assertEqual(t, 2.0, got.Mean)
+		assertNil(t, got.Quantiles)
+	})
+
+	t.Run("matches combined window", func(t *testing.T) {
+		a := []int{3, 6, 7, 8}
+		b := []int{8, 10, 13, 15, 16, 20}
+		want := makeFixed(append(slices.Clone(a), b...)...).Summary()
+		got := makeFixed(a...).Summary(0.5).Merge(makeFixed(b...).Summary())
+
+		assertEqual(t, want.Count, got.Count)
+		assertEqual(t, want.Min, got.Min)
+		assertEqual(t, want.Max, got.Max)
+		assertInDelta(t, want.Mean, got.Mean, 1e-12)
+		assertInDelta(t, want.Variance, got.Variance, 1e-12)
+		assertNil(t, got.Quantiles)
+	})
+
+	t.Run("exponential", func(t *testing.T) {
+		a := Exponential[int](0.5)
+		a.Put(1)
+		b := Exponential[int](0.5)
+		b.Put(3)
+
+		got := a.Summary().Merge(b.Summary())
+		assertEqual(t, 2, got.Count)
+		assertEqual(t, 1, got.Min)
+		assertEqual(t, 3, got.Max)
+		assertEqual(t, 2.0, got.Mean)
+		assertEqual(t, 1.0, got.Variance)
+	})
+}
+
+func Test_summary_MarshalBinary(t *testing.T) {
+	t.Run("int", func(t *testing.T) {
+		want := makeFixed(-3, 6, 7, 8, 8, 10, 13, 15, 16, 20).Summary(0.1, 0.5)
+		assertRoundTrip(t, want)
+	})
+
+	t.Run("int8", func(t *testing.T) {
+		assertRoundTrip(t, Summary[int8]{Count: 2, Min: -128, Max: 127, Mean: -0.5, Variance: 16256.25, StdDev: 127.5})
+	})
+
+	t.Run("uint64", func(t *testing.T) {
+		assertRoundTrip(t, Summary[uint64]{Count: 1, Min: math.MaxUint64, Max: math.MaxUint64, Mean: math.MaxUint64})
+	})
+
+	t.Run("float32", func(t *testing.T) {
+		assertRoundTrip(t, Summary[float32]{Count: 1, Min: 0.1, Max: 0.1, Mean: float64(float32(0.1))})
+	})
+
+	t.Run("float64", func(t *testing.T) {
+		w := Fixed[float64](3)
+		w.Put(-0.25)
+		w.Put(math.Pi)
+		assertRoundTrip(t, w.Summary(0.99))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		w := Fixed[int](3, WithStats(Mean))
+		w.Put(5)
+		assertRoundTrip(t, w.Summary(0.5))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		b, err := Summary[int]{}.MarshalBinary()
+		assertNil(t, err)
+		assertEqual(t, 22, len(b), "should be compact")
+		assertRoundTrip(t, Summary[int]{})
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		valid, _ := makeFixed(1, 2, 3).Summary(0.5).MarshalBinary()
+		cases := map[string][]byte{
+			"empty":            {},
+			"unknown version":  append([]byte{2}, valid[1:]...),
+			"truncated":        valid[:len(valid)-1],
+			"trailing data":    append(slices.Clone(valid), 0),
+			"quantile overrun": {1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0x7f},
+		}
+
+		for name, data := range cases {
+			var s Summary[int]
+			err := s.UnmarshalBinary(data)
+			assertEqual(t, ErrInvalidEncoding, err, name)
+		}
+	})
+}
+
+func assertRoundTrip[T Numeric](t *testing.T, want Summary[T]) {
+	b, err := want.MarshalBinary()
+	if !assertNil(t, err) {
+		return
+	}
+
+	var got Summary[T]
+	if !assertNil(t, got.UnmarshalBinary(b)) {
+		return
+	}
+
+	assertEqual(t, true, reflect.DeepEqual(want, got), fmt.Sprintf("want %+v, got %+v", want, got))
+}
@@ -0,0 +1,95 @@
+package mwnd
+
+import (
+	"slices"
+	"time"
+)
+
+// Tumbling partitions a stream of values into consecutive, non-overlapping windows.
+// Each time a window rolls over, its Summary is passed to a callback and the next
+// window starts empty.
+//
+// To receive summaries on a channel, send them from the callback:
+//
+//	ch := make(chan mwnd.Summary[int], 1)
+//	w, err := mwnd.NewTumbling[int](1000, time.Minute, func(s mwnd.Summary[int]) { ch <- s })
+type Tumbling[T Numeric] struct {
+	w         *FixedWindow[T]
+	every     time.Duration
+	start     time.Time
+	quantiles []float64
+	emit      func(Summary[T])
+	now       func() time.Time
+}
+
+// NewTumbling initializes a Tumbling window that rolls over once it holds size values
+// or, if every is positive, once a value is Put after the period of length every in
+// which the window started. Periods are aligned to multiples of every since the zero
+// time, so every = time.Minute rolls over on each minute of the wall clock.
+//
+// Each Summary includes the values of the requested quantiles. It returns an error
+// wrapping [ErrInvalidCapacity] if size is invalid, as for [NewFixed], [ErrNilEmit] if
+// emit is nil, or [ErrInvalidQuantile] if any quantile is outside of the range 0.0 to
+// 1.0, inclusive.
+func NewTumbling[T Numeric](size int, every time.Duration, emit func(Summary[T]), quantiles ...float64) (*Tumbling[T], error) {
+	if emit == nil {
+		return nil, ErrNilEmit
+	}
+	if err := validQuantiles(quantiles); err != nil {
+		return nil, err
+	}
+
+	w, err := NewFixed[T](size, WithStats(summaryStats(quantiles)))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tumbling[T]{
+		w:         w,
+		every:     every,
+		quantiles: slices.Clone(quantiles),
+		emit:      emit,
+		now:       time.Now,
+	}, nil
+}
+
+// Size returns the number of values in the current window.
+func (w *Tumbling[T]) Size() int {
+	return w.w.Size()
+}
+
+// Put adds a new value to the current window, first rolling over the window if its
+// period has elapsed, and then rolling over the window if it is full.
+//
+// Time complexity of O(log n), where n is the size of the window, except on rollover
+// which takes O(n).
+func (w *Tumbling[T]) Put(v T) {
+	if w.every > 0 {
+		now := w.now()
+		if w.w.Size() > 0 && now.Sub(w.start) >= w.every {
+			w.Flush()
+		}
+
+		if w.w.Size() == 0 {
+			w.start = now.Truncate(w.every)
+		}
+	}
+
+	w.w.Put(v)
+
+	if w.w.Size() == w.w.capacity() {
+		w.Flush()
+	}
+}
+
+// Flush rolls over the current window early, such as on shutdown or from a ticker
+// when values arrive too infrequently to roll over the window on Put. It does
+// nothing if the current window is empty.
+func (w *Tumbling[T]) Flush() {
+	if w.w.Size() == 0 {
+		return
+	}
+
+	w.emit(w.w.Summary(w.quantiles...))
+	w.w.Reset()
+}
@@ -0,0 +1,109 @@
+package mwnd
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_tumbling_Put(t *testing.T) {
+	t.Run("by count", func(t *testing.T) {
+		var got []Summary[int]
+		w, err := NewTumbling[int](3, 0, func(s Summary[int]) { got = append(got, s) }, 0.5)
+		assertNil(t, err)
+		for v := range 7 {
+			w.Put(v)
+		}
+
+		if !assertEqual(t, 2, len(got)) {
+			return
+		}
+		assertEqual(t, 1, w.Size())
+
+		assertEqual(t, 3, got[0].Count)
+		assertEqual(t, 0, got[0].Min)
+		assertEqual(t, 2, got[0].Max)
+		assertEqual(t, 1.0, got[0].Mean)
+		median, ok := got[0].Quantile(0.5)
+		assertEqual(t, true, ok)
+		assertEqual(t, 1, median)
+
+		assertEqual(t, 3, got[1].Count)
+		assertEqual(t, 3, got[1].Min)
+		assertEqual(t, 5, got[1].Max)
+		assertEqual(t, 4.0, got[1].Mean)
+	})
+
+	t.Run("by duration", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 12, 0, 30, 0, time.UTC)
+		var got []Summary[int]
+		w, err := NewTumbling[int](100, time.Minute, func(s Summary[int]) { got = append(got, s) })
+		assertNil(t, err)
+		w.now = func() time.Time { return now }
+
+		w.Put(1)
+		now = now.Add(20 * time.Second)
+		w.Put(2)
+		assertEqual(t, 0, len(got), "should not roll over within the minute")
+
+		now = now.Add(10 * time.Second)
+		w.Put(3)
+		if !assertEqual(t, 1, len(got), "should roll over on the minute") {
+			return
+		}
+		assertEqual(t, 2, got[0].Count)
+		assertEqual(t, 1.5, got[0].Mean)
+
+		now = now.Add(5 * time.Minute)
+		w.Put(4)
+		if !assertEqual(t, 2, len(got), "should roll over after idle minutes") {
+			return
+		}
+		assertEqual(t, 1, got[1].Count)
+		assertEqual(t, 3, got[1].Min)
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		var got []Summary[int]
+		w, err := NewTumbling[int](3, 0, func(s Summary[int]) { got = append(got, s) })
+		assertNil(t, err)
+		w.Flush()
+		assertEqual(t, 0, len(got), "should not emit an empty window")
+
+		w.Put(5)
+		w.Flush()
+		if !assertEqual(t, 1, len(got)) {
+			return
+		}
+		assertEqual(t, 1, got[0].Count)
+		assertEqual(t, 0, w.Size())
+	})
+}
+
+func Test_NewTumbling(t *testing.T) {
+	emit := func(Summary[int]) {}
+	w, err := NewTumbling[int](0, time.Minute, emit)
+	assertEqual(t, true, w == nil)
+	assertEqual(t, true, errors.Is(err, ErrInvalidCapacity))
+
+	w, err = NewTumbling[int](3, 0, emit, -0.5)
+	assertEqual(t, true, w == nil)
+	assertEqual(t, true, errors.Is(err, ErrInvalidQuantile))
+
+	w, err = NewTumbling[int](3, 0, nil)
+	assertEqual(t, true, w == nil)
+	assertEqual(t, true, errors.Is(err, ErrNilEmit))
+}
+
+func Test_tumbling_quantilesCopied(t *testing.T) {
+	var got Summary[int]
+	qs := []float64{0.5}
+	w, _ := NewTumbling[int](3, 0, func(s Summary[int]) { got = s }, qs...)
+	qs[0] = 1
+	w.Put(1)
+	w.Put(2)
+	w.Put(3)
+
+	_, ok := got.Quantile(0.5)
+	assertEqual(t, true, ok, "should not follow changes to the caller's quantiles")
+}
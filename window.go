@@ -1,6 +1,7 @@
 package mwnd
 
-type window[T Numeric] interface {
+// Window is the set of statistics common to all moving window implementations.
+type Window[T Numeric] interface {
 	Size() int
 	Put(T)
 	Min() T